@@ -14,6 +14,11 @@ func (e *CoerceErr) Error() string {
 	return fmt.Sprintf("venom: can not coerce %T to %q", e.From, e.To)
 }
 
+// Unwrap returns the underlying error which caused the coercion to fail, if any
+func (e *CoerceErr) Unwrap() error {
+	return e.Err
+}
+
 func coerceString(val interface{}) (string, error) {
 	if value, ok := val.(string); !ok {
 		return "", &CoerceErr{From: val, To: "string"}
@@ -73,11 +78,15 @@ func coerceBoolSlice(val interface{}) ([]bool, error) {
 }
 
 func coerceInt(val interface{}) (int, error) {
-	if value, ok := val.(int); !ok {
-		return 0, &CoerceErr{From: val, To: "int"}
-	} else {
+	if value, ok := val.(int); ok {
 		return value, nil
 	}
+
+	value, ok, err := signedNumber(val, 0)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "int", Err: err}
+	}
+	return int(value), nil
 }
 
 func coerceIntSlice(val interface{}) ([]int, error) {
@@ -113,11 +122,15 @@ func coerceIntSlice(val interface{}) ([]int, error) {
 }
 
 func coerceInt8(val interface{}) (int8, error) {
-	if value, ok := val.(int8); !ok {
-		return 0, &CoerceErr{From: val, To: "int8"}
-	} else {
+	if value, ok := val.(int8); ok {
 		return value, nil
 	}
+
+	value, ok, err := signedNumber(val, 8)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "int8", Err: err}
+	}
+	return int8(value), nil
 }
 
 func coerceInt8Slice(val interface{}) ([]int8, error) {
@@ -142,11 +155,15 @@ func coerceInt8Slice(val interface{}) ([]int8, error) {
 }
 
 func coerceInt16(val interface{}) (int16, error) {
-	if value, ok := val.(int16); !ok {
-		return 0, &CoerceErr{From: val, To: "int16"}
-	} else {
+	if value, ok := val.(int16); ok {
 		return value, nil
 	}
+
+	value, ok, err := signedNumber(val, 16)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "int16", Err: err}
+	}
+	return int16(value), nil
 }
 
 func coerceInt16Slice(val interface{}) ([]int16, error) {
@@ -171,11 +188,15 @@ func coerceInt16Slice(val interface{}) ([]int16, error) {
 }
 
 func coerceInt32(val interface{}) (int32, error) {
-	if value, ok := val.(int32); !ok {
-		return 0, &CoerceErr{From: val, To: "int32"}
-	} else {
+	if value, ok := val.(int32); ok {
 		return value, nil
 	}
+
+	value, ok, err := signedNumber(val, 32)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "int32", Err: err}
+	}
+	return int32(value), nil
 }
 
 func coerceInt32Slice(val interface{}) ([]int32, error) {
@@ -200,11 +221,15 @@ func coerceInt32Slice(val interface{}) ([]int32, error) {
 }
 
 func coerceInt64(val interface{}) (int64, error) {
-	if value, ok := val.(int64); !ok {
-		return 0, &CoerceErr{From: val, To: "int64"}
-	} else {
+	if value, ok := val.(int64); ok {
 		return value, nil
 	}
+
+	value, ok, err := signedNumber(val, 64)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "int64", Err: err}
+	}
+	return int64(value), nil
 }
 
 func coerceInt64Slice(val interface{}) ([]int64, error) {
@@ -229,11 +254,15 @@ func coerceInt64Slice(val interface{}) ([]int64, error) {
 }
 
 func coerceUint(val interface{}) (uint, error) {
-	if value, ok := val.(uint); !ok {
-		return 0, &CoerceErr{From: val, To: "uint"}
-	} else {
+	if value, ok := val.(uint); ok {
 		return value, nil
 	}
+
+	value, ok, err := unsignedNumber(val, 0)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "uint", Err: err}
+	}
+	return uint(value), nil
 }
 
 func coerceUintSlice(val interface{}) ([]uint, error) {
@@ -258,11 +287,15 @@ func coerceUintSlice(val interface{}) ([]uint, error) {
 }
 
 func coerceUint8(val interface{}) (uint8, error) {
-	if value, ok := val.(uint8); !ok {
-		return 0, &CoerceErr{From: val, To: "uint8"}
-	} else {
+	if value, ok := val.(uint8); ok {
 		return value, nil
 	}
+
+	value, ok, err := unsignedNumber(val, 8)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "uint8", Err: err}
+	}
+	return uint8(value), nil
 }
 
 func coerceUint8Slice(val interface{}) ([]uint8, error) {
@@ -287,11 +320,15 @@ func coerceUint8Slice(val interface{}) ([]uint8, error) {
 }
 
 func coerceUint16(val interface{}) (uint16, error) {
-	if value, ok := val.(uint16); !ok {
-		return 0, &CoerceErr{From: val, To: "uint16"}
-	} else {
+	if value, ok := val.(uint16); ok {
 		return value, nil
 	}
+
+	value, ok, err := unsignedNumber(val, 16)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "uint16", Err: err}
+	}
+	return uint16(value), nil
 }
 
 func coerceUint16Slice(val interface{}) ([]uint16, error) {
@@ -316,11 +353,15 @@ func coerceUint16Slice(val interface{}) ([]uint16, error) {
 }
 
 func coerceUint32(val interface{}) (uint32, error) {
-	if value, ok := val.(uint32); !ok {
-		return 0, &CoerceErr{From: val, To: "uint32"}
-	} else {
+	if value, ok := val.(uint32); ok {
 		return value, nil
 	}
+
+	value, ok, err := unsignedNumber(val, 32)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "uint32", Err: err}
+	}
+	return uint32(value), nil
 }
 
 func coerceUint32Slice(val interface{}) ([]uint32, error) {
@@ -345,11 +386,15 @@ func coerceUint32Slice(val interface{}) ([]uint32, error) {
 }
 
 func coerceUint64(val interface{}) (uint64, error) {
-	if value, ok := val.(uint64); !ok {
-		return 0, &CoerceErr{From: val, To: "uint64"}
-	} else {
+	if value, ok := val.(uint64); ok {
 		return value, nil
 	}
+
+	value, ok, err := unsignedNumber(val, 64)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "uint64", Err: err}
+	}
+	return uint64(value), nil
 }
 
 func coerceUint64Slice(val interface{}) ([]uint64, error) {
@@ -374,11 +419,15 @@ func coerceUint64Slice(val interface{}) ([]uint64, error) {
 }
 
 func coerceFloat32(val interface{}) (float32, error) {
-	if value, ok := val.(float32); !ok {
-		return 0, &CoerceErr{From: val, To: "float32"}
-	} else {
+	if value, ok := val.(float32); ok {
 		return value, nil
 	}
+
+	value, ok, err := floatNumber(val, 32)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "float32", Err: err}
+	}
+	return float32(value), nil
 }
 
 func coerceFloat32Slice(val interface{}) ([]float32, error) {
@@ -403,11 +452,15 @@ func coerceFloat32Slice(val interface{}) ([]float32, error) {
 }
 
 func coerceFloat64(val interface{}) (float64, error) {
-	if value, ok := val.(float64); !ok {
-		return 0, &CoerceErr{From: val, To: "float64"}
-	} else {
+	if value, ok := val.(float64); ok {
 		return value, nil
 	}
+
+	value, ok, err := floatNumber(val, 64)
+	if !ok || err != nil {
+		return 0, &CoerceErr{From: val, To: "float64", Err: err}
+	}
+	return float64(value), nil
 }
 
 func coerceFloat64Slice(val interface{}) ([]float64, error) {
@@ -172,6 +172,88 @@ func testAlias(t *testing.T, v ConfigStore) {
 	}
 }
 
+// levelConfigStore is a ConfigStore which implements the optional Unsetter,
+// LevelStore, LevelLister and Copier interfaces
+type levelConfigStore interface {
+	ConfigStore
+	Unsetter
+	LevelStore
+	LevelLister
+	Copier
+}
+
+func testReplaceLevel(t *testing.T, v levelConfigStore) {
+	assert.Nil(t, v.Level(FileLevel))
+	assert.Empty(t, v.Levels())
+
+	v.SetLevel(DefaultLevel, "db.port", 5432)
+	v.SetLevel(FileLevel, "db.host", "localhost")
+	v.SetLevel(FileLevel, "db.user", "admin")
+
+	v.ReplaceLevel(FileLevel, ConfigMap{
+		"db": map[string]interface{}{"host": "example.com"},
+	})
+
+	expect := ConfigMap{"db": ConfigMap{"host": "example.com"}}
+	assert.Equal(t, expect, v.Level(FileLevel))
+
+	host, _ := v.Find("db.host")
+	assert.Equal(t, "example.com", host)
+	_, ok := v.Find("db.user")
+	assert.False(t, ok)
+	port, _ := v.Find("db.port")
+	assert.Equal(t, 5432, port)
+
+	// the returned level must be a copy of the stored level
+	level := v.Level(FileLevel)
+	level["db"].(ConfigMap)["host"] = "modified"
+	host, _ = v.Find("db.host")
+	assert.Equal(t, "example.com", host)
+
+	v.ReplaceLevel(EnvironmentLevel, ConfigMap{"db": ConfigMap{"host": "env"}})
+	host, _ = v.Find("db.host")
+	assert.Equal(t, "env", host)
+	assert.Equal(t, []ConfigLevel{DefaultLevel, FileLevel, EnvironmentLevel}, v.Levels())
+
+	// copies must be independent of the original store
+	c := v.Copy()
+	c.SetLevel(EnvironmentLevel, "db.host", "copy")
+	c.(LevelStore).ReplaceLevel(DefaultLevel, ConfigMap{})
+	host, _ = v.Find("db.host")
+	assert.Equal(t, "env", host)
+	port, _ = v.Find("db.port")
+	assert.Equal(t, 5432, port)
+	host, _ = c.Find("db.host")
+	assert.Equal(t, "copy", host)
+}
+
+func testUnset(t *testing.T, v levelConfigStore) {
+	v.SetLevel(DefaultLevel, "db.host", "localhost")
+	v.SetLevel(OverrideLevel, "db.host", "example.com")
+	v.SetLevel(OverrideLevel, "db.opts.ssl", true)
+	v.SetLevel(OverrideLevel, "db.user", "admin")
+
+	v.Unset(OverrideLevel, "db.host")
+	host, _ := v.Find("db.host")
+	assert.Equal(t, "localhost", host)
+
+	// removing the last key of a nested map removes the map
+	v.Unset(OverrideLevel, "db.opts.ssl")
+	assert.Equal(t, ConfigMap{"db": ConfigMap{"user": "admin"}}, v.Level(OverrideLevel))
+
+	// unsetting missing keys and levels is a no-op
+	v.Unset(OverrideLevel, "db.missing.key")
+	v.Unset(OverrideLevel, "db.user.name")
+	v.Unset(FileLevel, "db.user")
+	assert.Equal(t, ConfigMap{"db": ConfigMap{"user": "admin"}}, v.Level(OverrideLevel))
+	assert.Nil(t, v.Level(FileLevel))
+
+	v.Unset(OverrideLevel, "db.user")
+	assert.Equal(t, ConfigMap{}, v.Level(OverrideLevel))
+	host, _ = v.Find("db.host")
+	assert.Equal(t, "localhost", host)
+}
+
 func testEdgeCases(t *testing.T, v ConfigStore) {
 	testIO := []struct {
 		tc       string
@@ -352,20 +352,23 @@ func (d *decoder) coerceSlice(val interface{}, to reflect.Kind, field reflect.Va
 		}
 		field.Set(reflect.ValueOf(actual))
 	case reflect.Struct:
-		unAddressableSlice := reflect.MakeSlice(field.Type(), field.Len(), field.Cap())
-		actual := reflect.New(unAddressableSlice.Type())
-
+		if val == nil {
+			return nil
+		}
 		sliceVal := reflect.ValueOf(val)
+		if sliceVal.Kind() != reflect.Slice {
+			return &CoerceErr{From: val, To: field.Type().String()}
+		}
+
+		actual := reflect.MakeSlice(field.Type(), 0, sliceVal.Len())
 		for i := 0; i < sliceVal.Len(); i++ {
 			item := reflect.New(sliceType)
-			if item.CanAddr() {
-				if err = d.value(item.Addr()); err != nil {
-					return err
-				}
+			if err = d.structItem(sliceVal.Index(i).Interface(), item); err != nil {
+				return err
 			}
-			reflectAppend(actual, item)
+			actual = reflect.Append(actual, item.Elem())
 		}
-		field.Set(reflect.Indirect(actual))
+		field.Set(actual)
 	case reflect.Slice:
 		// we've hit a multidimensional slice so we need to recursively build
 		// up the inner slices
@@ -386,7 +389,22 @@ func (d *decoder) coerceSlice(val interface{}, to reflect.Kind, field reflect.Va
 	return err
 }
 
-func reflectAppend(slice reflect.Value, item reflect.Value) {
-	sliceVal := reflect.ValueOf(slice.Interface())
-	sliceVal.Elem().Set(reflect.Append(sliceVal.Elem(), reflect.Indirect(item)))
+// structItem decodes the provided map, an item of a slice, into the struct
+// pointed to by item. An error is returned if the item is not a map.
+func (d *decoder) structItem(val interface{}, item reflect.Value) error {
+	if actual, ok := val.(map[interface{}]interface{}); ok {
+		val = mapInterfaceInterfaceToStrInterface(actual)
+	}
+	data, ok := toStringMap(val)
+	if !ok {
+		return &CoerceErr{From: val, To: item.Elem().Type().String()}
+	}
+
+	// the fields of the item are found within a Venom holding only the item,
+	// in the same manner as the fields of the top level struct
+	itemData := New()
+	itemData.Merge(DefaultLevel, make(ConfigMap).merge(data))
+
+	var itemDecoder decoder
+	return itemDecoder.init(itemData).value(item)
 }
@@ -0,0 +1,373 @@
+package venom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A FileOrder determines the order in which the files found within a directory
+// are loaded
+type FileOrder int
+
+const (
+	// LexicalOrder loads files in lexical order of their paths, relative to
+	// the directory being loaded. This is the default.
+	LexicalOrder FileOrder = iota
+
+	// NumericOrder loads files in lexical order, except that runs of digits
+	// are compared numerically, meaning that "9-site.json" is loaded before
+	// "10-base.json".
+	NumericOrder
+)
+
+// DirectoryOptions configures how the config files within a directory are
+// discovered and loaded
+type DirectoryOptions struct {
+	// Recurse toggles whether or not files within sub-directories are loaded
+	Recurse bool
+
+	// Include is a collection of glob patterns, as understood by
+	// filepath.Match. If non-empty, only files matching at least one pattern
+	// are loaded. Patterns containing a path separator are matched against
+	// the slash-separated path relative to the directory being loaded, all
+	// other patterns are matched against the base name of each file.
+	Include []string
+
+	// Exclude is a collection of glob patterns, matched in the same manner as
+	// Include. Files and sub-directories matching any pattern are skipped.
+	Exclude []string
+
+	// FollowSymlinks toggles whether or not symbolic links to directories are
+	// walked when recursing. Symbolic links to files are always loaded.
+	FollowSymlinks bool
+
+	// SkipHidden toggles whether or not files and sub-directories whose names
+	// begin with a "." are skipped.
+	SkipHidden bool
+
+	// Order determines the order in which discovered files are loaded. Note
+	// that files are always applied according to their sub-level, the order
+	// only affects files which share a sub-level.
+	Order FileOrder
+
+	// Atomic toggles whether or not every file is parsed before any of them
+	// are loaded. When enabled, either all files are loaded or, if any file
+	// fails to load, none of them are and a DirectoryError describing every
+	// failure is returned. When disabled, files are loaded in order until the
+	// first failure.
+	Atomic bool
+}
+
+// A DirectoryError is returned when one or more files within a directory fail
+// to load atomically. Errors maps the path of each failing file or directory
+// to the error encountered while loading it.
+type DirectoryError struct {
+	Dir    string
+	Errors map[string]error
+}
+
+// Error implements the error interface and returns a custom error message
+// listing each failure for the current DirectoryError instance
+func (e *DirectoryError) Error() string {
+	paths := make([]string, 0, len(e.Errors))
+	for path := range e.Errors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "venom: failed to load %d file(s) in %q", len(paths), e.Dir)
+	for _, path := range paths {
+		fmt.Fprintf(&b, "\n\t%s: %s", path, e.Errors[path])
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors encountered for each failing file, ordered by
+// path, so that they may be inspected with errors.Is and errors.As
+func (e *DirectoryError) Unwrap() []error {
+	return sortedErrors(e.Errors)
+}
+
+// Is returns true if the error encountered for any failing file matches the
+// target, as reported by errors.Is. This allows errors.Is to inspect the
+// errors of each file on versions of Go which do not support Unwrap methods
+// returning multiple errors.
+func (e *DirectoryError) Is(target error) bool {
+	for _, err := range sortedErrors(e.Errors) {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error encountered for a failing file, ordered by path,
+// which matches the target, as reported by errors.As. This allows errors.As to
+// inspect the errors of each file on versions of Go which do not support
+// Unwrap methods returning multiple errors.
+func (e *DirectoryError) As(target interface{}) bool {
+	for _, err := range sortedErrors(e.Errors) {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedErrors returns the provided errors ordered by their associated path
+func sortedErrors(errs map[string]error) []error {
+	paths := make([]string, 0, len(errs))
+	for path := range errs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	sorted := make([]error, 0, len(paths))
+	for _, path := range paths {
+		sorted = append(sorted, errs[path])
+	}
+	return sorted
+}
+
+// LoadDirectory loads any config files found in the provided directory,
+// optionally recursing into any sub-directories
+func (v *Venom) LoadDirectory(dir string, recurse bool) error {
+	return v.LoadDirectoryAt(FileLevel, dir, recurse)
+}
+
+// LoadDirectoryAt loads any config files found in the provided directory into
+// the specified ConfigLevel, optionally recursing into any sub-directories.
+//
+// Each file is loaded into its own sub-level of the ConfigLevel, as determined
+// by FileSubLevel, meaning that files such as "90-local.json" take precedence
+// over files such as "10-base.json".
+func (v *Venom) LoadDirectoryAt(level ConfigLevel, dir string, recurse bool) error {
+	return v.LoadDirectoryWith(level, dir, DirectoryOptions{Recurse: recurse})
+}
+
+// LoadDirectoryWith loads any config files found in the provided directory
+// into the specified ConfigLevel, as configured by the provided
+// DirectoryOptions
+func (v *Venom) LoadDirectoryWith(level ConfigLevel, dir string, opts DirectoryOptions) error {
+	return v.loadSource(&directorySource{dir: dir, at: level, opts: opts})
+}
+
+// readFile loads the config data from the file at the provided path, using the
+// IOFileLoader registered for the files extension. If signature verification is
+// enabled, the signature of the file is verified before it is loaded.
+func (v *Venom) readFile(name string) (map[string]interface{}, error) {
+	if v.signatures.enabled() {
+		return v.readSignedFile(name, func(name string) (io.ReadCloser, error) {
+			return os.Open(name)
+		})
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return v.decodeFile(file, name)
+}
+
+// A directoryWalker discovers the config files within a directory
+type directoryWalker struct {
+	root    string
+	opts    DirectoryOptions
+	visited map[string]bool
+	files   []string
+	errs    map[string]error
+}
+
+// findFiles returns the paths of all files within dir which have a registered
+// IOFileLoader, ordered as specified by the provided DirectoryOptions, along
+// with any errors encountered while walking the directory keyed by path
+func findFiles(dir string, opts DirectoryOptions) ([]string, map[string]error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, map[string]error{dir: err}
+	}
+
+	w := &directoryWalker{
+		root:    root,
+		opts:    opts,
+		visited: make(map[string]bool),
+		errs:    make(map[string]error),
+	}
+
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			w.errs[pattern] = fmt.Errorf("venom: invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if len(w.errs) > 0 {
+		return nil, w.errs
+	}
+
+	w.walk(root)
+	sortFiles(w.files, root, opts.Order)
+	sortBySubLevel(w.files)
+	return w.files, w.errs
+}
+
+// walk walks the directory at the provided path, which may be a symbolic link
+// to a directory within the root
+func (w *directoryWalker) walk(dir string) {
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		if w.visited[real] {
+			return
+		}
+		w.visited[real] = true
+	}
+
+	// walk the directory with a trailing separator, ensuring that symbolic
+	// links to directories are resolved
+	root := dir + string(filepath.Separator)
+	_ = filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			w.errs[filepath.Clean(file)] = err
+			return nil
+		}
+
+		isRoot := file == root
+		if !isRoot && w.skip(file) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case info.IsDir():
+			if !isRoot && !w.opts.Recurse {
+				// don't recurse into subdirectories
+				return filepath.SkipDir
+			}
+		case info.Mode()&os.ModeSymlink != 0:
+			w.walkSymlink(file)
+		case w.include(file):
+			w.files = append(w.files, file)
+		}
+		return nil
+	})
+}
+
+// walkSymlink resolves the symbolic link at the provided path, walking it if it
+// is a directory or adding it to the discovered files otherwise
+func (w *directoryWalker) walkSymlink(file string) {
+	info, err := os.Stat(file)
+	if err != nil {
+		w.errs[file] = err
+		return
+	}
+
+	if !info.IsDir() {
+		if w.include(file) {
+			w.files = append(w.files, file)
+		}
+		return
+	}
+
+	if w.opts.Recurse && w.opts.FollowSymlinks {
+		w.walk(file)
+	}
+}
+
+// skip returns true if the provided file or directory is hidden and hidden
+// files are skipped, or if it matches an Exclude pattern
+func (w *directoryWalker) skip(file string) bool {
+	if w.opts.SkipHidden && strings.HasPrefix(filepath.Base(file), ".") {
+		return true
+	}
+	return w.matches(file, w.opts.Exclude)
+}
+
+// include returns true if the provided file has a registered IOFileLoader and
+// matches any Include patterns
+func (w *directoryWalker) include(file string) bool {
+	if !hasLoader(file) {
+		return false
+	}
+	return len(w.opts.Include) == 0 || w.matches(file, w.opts.Include)
+}
+
+// matches returns true if the provided file matches any of the provided glob
+// patterns
+func (w *directoryWalker) matches(file string, patterns []string) bool {
+	rel, err := filepath.Rel(w.root, file)
+	if err != nil {
+		rel = file
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range patterns {
+		target := filepath.Base(file)
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if matched, _ := filepath.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sortFiles sorts the provided files, by their path relative to root, in the
+// provided FileOrder
+func sortFiles(files []string, root string, order FileOrder) {
+	rel := func(file string) string {
+		if r, err := filepath.Rel(root, file); err == nil {
+			return filepath.ToSlash(r)
+		}
+		return filepath.ToSlash(file)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if order == NumericOrder {
+			return numericLess(rel(files[i]), rel(files[j]))
+		}
+		return rel(files[i]) < rel(files[j])
+	})
+}
+
+// numericLess compares the provided strings lexically, except that runs of
+// digits are compared by their numeric value
+func numericLess(a, b string) bool {
+	for len(a) > 0 && len(b) > 0 {
+		aDigits, bDigits := leadingDigits(a), leadingDigits(b)
+		if aDigits == "" || bDigits == "" {
+			if a[0] != b[0] {
+				return a[0] < b[0]
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		aTrimmed := strings.TrimLeft(aDigits, "0")
+		bTrimmed := strings.TrimLeft(bDigits, "0")
+		if len(aTrimmed) != len(bTrimmed) {
+			return len(aTrimmed) < len(bTrimmed)
+		}
+		if aTrimmed != bTrimmed {
+			return aTrimmed < bTrimmed
+		}
+		a, b = a[len(aDigits):], b[len(bDigits):]
+	}
+	return len(a) < len(b)
+}
+
+// leadingDigits returns the run of digits at the start of s
+func leadingDigits(s string) string {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	return s[:end]
+}
@@ -0,0 +1,239 @@
+package venom
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFiles writes the provided file contents, keyed by their relative path,
+// into dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadDirectoryWith(t *testing.T) {
+	testIO := []struct {
+		tc     string
+		files  map[string]string
+		links  [][2]string
+		opts   DirectoryOptions
+		expect ConfigMap
+	}{
+		{
+			tc: "should match extensions exactly and case-insensitively",
+			files: map[string]string{
+				"config.JSON": `{"upper": true}`,
+				"foojson":     `{"suffix": true}`,
+				"config.json": `{"lower": true}`,
+			},
+			expect: ConfigMap{"upper": true, "lower": true},
+		},
+		{
+			tc: "should not recurse unless requested",
+			files: map[string]string{
+				"config.json":     `{"foo": "bar"}`,
+				"sub/config.json": `{"foo": "baz"}`,
+			},
+			expect: ConfigMap{"foo": "bar"},
+		},
+		{
+			tc: "should only load included files",
+			files: map[string]string{
+				"app.json":      `{"app": true}`,
+				"db.json":       `{"db": true}`,
+				"sub/app.json":  `{"sub": true}`,
+				"sub/other.xml": `<Config><other>true</other></Config>`,
+			},
+			opts:   DirectoryOptions{Recurse: true, Include: []string{"app.*", "sub/*.xml"}},
+			expect: ConfigMap{"app": true, "sub": true, "other": "true"},
+		},
+		{
+			tc: "should skip excluded files and directories",
+			files: map[string]string{
+				"app.json":        `{"app": true}`,
+				"app.local.json":  `{"local": true}`,
+				"vendor/lib.json": `{"vendor": true}`,
+			},
+			opts:   DirectoryOptions{Recurse: true, Exclude: []string{"*.local.json", "vendor"}},
+			expect: ConfigMap{"app": true},
+		},
+		{
+			tc: "should skip hidden files and directories",
+			files: map[string]string{
+				"app.json":         `{"app": true}`,
+				".hidden.json":     `{"hidden": true}`,
+				".git/config.json": `{"git": true}`,
+			},
+			opts:   DirectoryOptions{Recurse: true, SkipHidden: true},
+			expect: ConfigMap{"app": true},
+		},
+		{
+			tc: "should load hidden files by default",
+			files: map[string]string{
+				".hidden.json": `{"hidden": true}`,
+			},
+			expect: ConfigMap{"hidden": true},
+		},
+		{
+			tc: "should order files lexically by default",
+			files: map[string]string{
+				"app-9.json":  `{"foo": "nine"}`,
+				"app-10.json": `{"foo": "ten"}`,
+			},
+			expect: ConfigMap{"foo": "nine"},
+		},
+		{
+			tc: "should order files numerically",
+			files: map[string]string{
+				"app-9.json":  `{"foo": "nine"}`,
+				"app-10.json": `{"foo": "ten"}`,
+			},
+			opts:   DirectoryOptions{Order: NumericOrder},
+			expect: ConfigMap{"foo": "ten"},
+		},
+		{
+			tc: "should load symlinked files but not walk symlinked directories",
+			files: map[string]string{
+				"target/config.json": `{"foo": "bar"}`,
+				"other/link.json":    `{"linked": true}`,
+			},
+			links: [][2]string{
+				{"root/file.json", "other/link.json"},
+				{"root/dir", "target"},
+			},
+			opts:   DirectoryOptions{Recurse: true},
+			expect: ConfigMap{"linked": true},
+		},
+		{
+			tc: "should walk symlinked directories when following symlinks",
+			files: map[string]string{
+				"target/config.json": `{"foo": "bar"}`,
+			},
+			links: [][2]string{
+				{"root/dir", "target"},
+				{"target/loop", "target"},
+				{"root/self", "root"},
+			},
+			opts:   DirectoryOptions{Recurse: true, FollowSymlinks: true},
+			expect: ConfigMap{"foo": "bar"},
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			base := t.TempDir()
+			writeFiles(t, base, test.files)
+
+			dir := base
+			if len(test.links) > 0 {
+				dir = filepath.Join(base, "root")
+				for _, link := range test.links {
+					path, target := filepath.Join(base, link[0]), link[1]
+					_ = os.MkdirAll(filepath.Dir(path), 0755)
+					if err := os.Symlink(filepath.Join(base, target), path); err != nil {
+						t.Skip(err)
+					}
+				}
+			}
+
+			v := New()
+			assert.NoError(t, v.LoadDirectoryWith(FileLevel, dir, test.opts))
+			st := v.Store.(*DefaultConfigStore)
+			assert.EqualValues(t, test.expect, st.config[FileLevel])
+		})
+	}
+}
+
+func TestLoadDirectoryErrors(t *testing.T) {
+	t.Run("should error on a missing directory", func(t *testing.T) {
+		v := New()
+		err := v.LoadDirectory(filepath.Join(t.TempDir(), "missing"), false)
+		assert.True(t, errors.Is(err, os.ErrNotExist), "%v", err)
+	})
+
+	t.Run("should error on an invalid pattern", func(t *testing.T) {
+		v := New()
+		err := v.LoadDirectoryWith(FileLevel, t.TempDir(), DirectoryOptions{Include: []string{"["}})
+		assert.True(t, errors.Is(err, filepath.ErrBadPattern), "%v", err)
+	})
+
+	t.Run("should leave no partial state when loading atomically", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"a.json": `{"a": true}`,
+			"b.json": `{"b": `,
+			"c.json": `{"c": true}`,
+			"d.json": `{"d": }`,
+		})
+
+		v := New()
+		err := v.LoadDirectoryWith(FileLevel, dir, DirectoryOptions{Atomic: true})
+
+		var dirErr *DirectoryError
+		if assert.True(t, errors.As(err, &dirErr), "%v", err) {
+			assert.Len(t, dirErr.Errors, 2)
+			assert.Contains(t, dirErr.Errors, filepath.Join(dir, "b.json"))
+			assert.Contains(t, dirErr.Errors, filepath.Join(dir, "d.json"))
+			assert.Contains(t, err.Error(), "b.json")
+			assert.Contains(t, err.Error(), "d.json")
+		}
+
+		var syntaxErr *json.SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr))
+		// errors.As and errors.Is only use multi-error Unwrap methods from
+		// Go 1.20, so the DirectoryError must match the errors of its files
+		assert.True(t, dirErr.As(&syntaxErr))
+		assert.True(t, dirErr.Is(dirErr.Errors[filepath.Join(dir, "b.json")]))
+		assert.False(t, dirErr.Is(os.ErrNotExist))
+		assert.Equal(t, 0, v.Size())
+	})
+
+	t.Run("should load every file atomically", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"a.json": `{"a": true}`,
+			"b.json": `{"b": true}`,
+		})
+
+		v := New()
+		assert.NoError(t, v.LoadDirectoryWith(FileLevel, dir, DirectoryOptions{Atomic: true}))
+		assert.Equal(t, true, v.Get("a"))
+		assert.Equal(t, true, v.Get("b"))
+	})
+}
+
+func TestNumericLess(t *testing.T) {
+	testIO := []struct {
+		a, b   string
+		expect bool
+	}{
+		{"9-site.json", "10-base.json", true},
+		{"10-base.json", "9-site.json", false},
+		{"app-2.json", "app-10.json", true},
+		{"a.json", "b.json", true},
+		{"app-01.json", "app-1.json", false},
+		{"app.json", "app.json", false},
+		{"app", "app-1", true},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.a+"<"+test.b, func(t *testing.T) {
+			assert.Equal(t, test.expect, numericLess(test.a, test.b))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package venom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	gzipKey = "gz"
+	encKey  = "enc"
+
+	// DefaultKeyEnv is the environment variable from which the key used to
+	// decrypt ".enc" config files is read, unless another KeyProvider is
+	// registered via AESGCMDecoder
+	DefaultKeyEnv = "VENOM_CONFIG_KEY"
+)
+
+// encodingMap is the collection of outer file extensions, such as the "gz" of
+// "config.json.gz", to the IOFileDecoders that decode files with those
+// extensions
+var encodingMap = map[string]IOFileDecoder{
+	gzipKey: GzipDecoder,
+	encKey:  AESGCMDecoder(EnvKeyProvider(DefaultKeyEnv)),
+}
+
+// IOFileDecoder is the function signature for a function which decodes a
+// compressed or encrypted config file, returning an io.Reader of the decoded
+// contents
+type IOFileDecoder func(io.Reader) (io.Reader, error)
+
+// RegisterEncoding registers an IOFileDecoder for the provided outer file
+// extension. Files with stacked extensions, such as "config.json.gz", are
+// decoded with the IOFileDecoder registered for each outer extension before
+// the IOFileLoader registered for the inner extension loads them. Extensions
+// are matched case-insensitively.
+func RegisterEncoding(ext string, decoder IOFileDecoder) {
+	encodingMap[normalizeExtension(ext)] = decoder
+}
+
+// fileFormat returns the extension of the IOFileLoader for the named file,
+// along with the extensions of any IOFileDecoders that must be applied first,
+// outermost first. For example, "config.json.gz" has a format of "json" and
+// encodings of ["gz"].
+func fileFormat(name string) (format string, encodings []string) {
+	for {
+		ext := filepath.Ext(name)
+		key := normalizeExtension(ext)
+		if _, ok := encodingMap[key]; !ok || ext == "" {
+			return key, encodings
+		}
+		encodings = append(encodings, key)
+		name = strings.TrimSuffix(name, ext)
+	}
+}
+
+// GzipDecoder is an IOFileDecoder which decompresses gzip compressed files
+func GzipDecoder(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+// A KeyProvider returns the key used to encrypt and decrypt config files
+type KeyProvider func() ([]byte, error)
+
+// EnvKeyProvider returns a KeyProvider which reads a base64 encoded key from
+// the named environment variable
+func EnvKeyProvider(name string) KeyProvider {
+	return func() ([]byte, error) {
+		val, ok := os.LookupEnv(name)
+		if !ok || val == "" {
+			return nil, fmt.Errorf("venom: encryption key environment variable %s is not set", name)
+		}
+		return decodeKey(val)
+	}
+}
+
+// FileKeyProvider returns a KeyProvider which reads a base64 encoded key from
+// the named file
+func FileKeyProvider(name string) KeyProvider {
+	return func() ([]byte, error) {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return decodeKey(string(b))
+	}
+}
+
+// decodeKey decodes a base64 encoded key, ignoring surrounding whitespace
+func decodeKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("venom: invalid encryption key: %w", err)
+	}
+	return key, nil
+}
+
+// ErrCiphertextTooShort is returned when an encrypted config file is too short
+// to contain a nonce
+var ErrCiphertextTooShort = errors.New("venom: ciphertext too short")
+
+// AESGCMDecoder returns an IOFileDecoder which decrypts config files encrypted
+// with AES-GCM, using the key returned by the provided KeyProvider. The key
+// must be 16, 24 or 32 bytes long, selecting AES-128, AES-192 or AES-256.
+// Encrypted files consist of a 12 byte nonce followed by the sealed config
+// data, as produced by EncryptAESGCM.
+//
+// An AESGCMDecoder using the key from DefaultKeyEnv is registered for the
+// ".enc" extension. To use a different KeyProvider, register another decoder:
+//
+//	RegisterEncoding("enc", AESGCMDecoder(FileKeyProvider("/etc/app/config.key")))
+func AESGCMDecoder(keys KeyProvider) IOFileDecoder {
+	return func(r io.Reader) (io.Reader, error) {
+		key, err := keys()
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := newAESGCM(key)
+		if err != nil {
+			return nil, err
+		}
+
+		ciphertext, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(ciphertext) < aead.NonceSize() {
+			return nil, ErrCiphertextTooShort
+		}
+
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, sealed, nil)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(plaintext), nil
+	}
+}
+
+// EncryptAESGCM encrypts the provided config data with AES-GCM, using a random
+// nonce, returning data which can be decrypted by an AESGCMDecoder using the
+// same key
+func EncryptAESGCM(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,190 @@
+package venom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encryptBytes(t *testing.T, key, data []byte) []byte {
+	encrypted, err := EncryptAESGCM(key, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encrypted
+}
+
+func TestFileFormat(t *testing.T) {
+	testIO := []struct {
+		name      string
+		format    string
+		encodings []string
+	}{
+		{name: "config.json", format: "json"},
+		{name: "config.JSON.GZ", format: "json", encodings: []string{"gz"}},
+		{name: "dir.d/config.json.gz.enc", format: "json", encodings: []string{"enc", "gz"}},
+		{name: "config.gz", format: "", encodings: []string{"gz"}},
+		{name: "config", format: ""},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.name, func(t *testing.T) {
+			format, encodings := fileFormat(test.name)
+			assert.Equal(t, test.format, format)
+			assert.Equal(t, test.encodings, encodings)
+		})
+	}
+}
+
+func TestLoadEncodedFiles(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	t.Setenv(DefaultKeyEnv, base64.StdEncoding.EncodeToString(key))
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json.gz":         string(gzipBytes(t, `{"compressed": true}`)),
+		"config.json.enc":        string(encryptBytes(t, key, []byte(`{"encrypted": true}`))),
+		"config.json.gz.enc":     string(encryptBytes(t, key, gzipBytes(t, `{"both": true}`))),
+		"config.txt.gz":          string(gzipBytes(t, `ignored`)),
+		"invalid/config.json.gz": `this is not gzip data`,
+	})
+
+	t.Run("should load each file", func(t *testing.T) {
+		for _, name := range []string{"config.json.gz", "config.json.enc", "config.json.gz.enc"} {
+			v := New()
+			assert.NoError(t, v.LoadFile(filepath.Join(dir, name)), name)
+		}
+	})
+
+	t.Run("should load encoded files in directories", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadDirectory(dir, false))
+		assert.Equal(t, true, v.Get("compressed"))
+		assert.Equal(t, true, v.Get("encrypted"))
+		assert.Equal(t, true, v.Get("both"))
+	})
+
+	t.Run("should load encoded readers", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadBytes(gzipBytes(t, `{"foo": "bar"}`), ".json.gz", OverrideLevel))
+		assert.Equal(t, "bar", v.Get("foo"))
+	})
+
+	t.Run("should report invalid compressed files", func(t *testing.T) {
+		err := New().LoadFile(filepath.Join(dir, "invalid", "config.json.gz"))
+
+		var loadErr *LoadError
+		if assert.True(t, errors.As(err, &loadErr), "%v", err) {
+			assert.Equal(t, filepath.Join(dir, "invalid", "config.json.gz"), loadErr.Path)
+			assert.Equal(t, gzipKey, loadErr.Format)
+			assert.True(t, errors.Is(err, gzip.ErrHeader))
+		}
+	})
+
+	t.Run("should reject files encrypted with another key", func(t *testing.T) {
+		t.Setenv(DefaultKeyEnv, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)))
+		v := New()
+		err := v.LoadFile(filepath.Join(dir, "config.json.enc"))
+
+		var loadErr *LoadError
+		if assert.True(t, errors.As(err, &loadErr), "%v", err) {
+			assert.Equal(t, encKey, loadErr.Format)
+		}
+		assert.Equal(t, 0, v.Size())
+	})
+
+	t.Run("should report a missing key", func(t *testing.T) {
+		t.Setenv(DefaultKeyEnv, "")
+		err := New().LoadFile(filepath.Join(dir, "config.json.enc"))
+		assert.Contains(t, err.Error(), DefaultKeyEnv)
+	})
+
+	t.Run("should enforce limits on decoded data", func(t *testing.T) {
+		v := New()
+		v.SetLimits(LoadLimits{MaxFileSize: 64})
+
+		bomb := gzipBytes(t, `{"data": "`+strings.Repeat("a", 1<<16)+`"}`)
+		err := v.LoadBytes(bomb, "json.gz", FileLevel)
+
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr), "%v", err)
+	})
+
+	t.Run("should enforce limits on encoded data", func(t *testing.T) {
+		v := New()
+		v.SetLimits(LoadLimits{MaxFileSize: 64})
+
+		large := encryptBytes(t, key, []byte(`{"data": "`+strings.Repeat("a", 128)+`"}`))
+		err := v.LoadBytes(large, "json.enc", FileLevel)
+
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr), "%v", err)
+		var loadErr *LoadError
+		if assert.True(t, errors.As(err, &loadErr), "%v", err) {
+			assert.Equal(t, encKey, loadErr.Format)
+		}
+	})
+}
+
+func TestKeyProviders(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.key":      encoded + "\n",
+		"invalid.key":     "not base64!",
+		"config.json.enc": string(encryptBytes(t, key, []byte(`{"foo": "bar"}`))),
+	})
+
+	t.Run("FileKeyProvider", func(t *testing.T) {
+		actual, err := FileKeyProvider(filepath.Join(dir, "config.key"))()
+		assert.NoError(t, err)
+		assert.Equal(t, key, actual)
+
+		_, err = FileKeyProvider(filepath.Join(dir, "invalid.key"))()
+		assert.Error(t, err)
+
+		_, err = FileKeyProvider(filepath.Join(dir, "missing.key"))()
+		assert.Error(t, err)
+	})
+
+	t.Run("EnvKeyProvider", func(t *testing.T) {
+		t.Setenv("TEST_VENOM_KEY", encoded)
+		actual, err := EnvKeyProvider("TEST_VENOM_KEY")()
+		assert.NoError(t, err)
+		assert.Equal(t, key, actual)
+	})
+
+	t.Run("should use registered decoders", func(t *testing.T) {
+		defer RegisterEncoding(encKey, encodingMap[encKey])
+		RegisterEncoding(".ENC", AESGCMDecoder(FileKeyProvider(filepath.Join(dir, "config.key"))))
+
+		v := New()
+		assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json.enc")))
+		assert.Equal(t, "bar", v.Get("foo"))
+	})
+
+	t.Run("should reject short ciphertexts", func(t *testing.T) {
+		_, err := AESGCMDecoder(func() ([]byte, error) { return key, nil })(strings.NewReader("short"))
+		assert.Equal(t, ErrCiphertextTooShort, err)
+	})
+}
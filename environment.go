@@ -1,13 +1,16 @@
 package venom
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 )
 
-var defaultEnvResolver = &EnvironmentVariableResolver{}
-
 // EnvSeparator is used as the delimiter for separating keys prior to looking
 // them up in the current environment.
 //
@@ -15,17 +18,117 @@ var defaultEnvResolver = &EnvironmentVariableResolver{}
 // for an environment variable named "LOG_LEVEL".
 var EnvSeparator = "_"
 
+// EnvFileSuffix is appended to the name of an environment variable to find the
+// variable naming a file which contains its value, when file indirection is
+// enabled.
+//
+// ie, an EnvFileSuffix of "_FILE" will result in a lookup for "db.password"
+// reading the file named by "DB_PASSWORD_FILE" if "DB_PASSWORD" is not set.
+var EnvFileSuffix = "_FILE"
+
+// EnvListSeparator is used to split the values of the ListKeys and MapKeys of
+// an EnvironmentVariableResolver which is not configured with a ListSeparator.
+var EnvListSeparator = ","
+
+// EnvKeyValueSeparator is used to split the items of the values of the MapKeys
+// of an EnvironmentVariableResolver which is not configured with a
+// KeyValueSeparator.
+var EnvKeyValueSeparator = "="
+
+// An EnvFileError is returned when the file named by an environment variable
+// can not be read
+type EnvFileError struct {
+	Name string
+	Path string
+	Err  error
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current EnvFileError instance
+func (e *EnvFileError) Error() string {
+	return fmt.Sprintf("venom: %s=%s: %s", e.Name, e.Path, e.Err)
+}
+
+// Unwrap returns the reason the file could not be read
+func (e *EnvFileError) Unwrap() error {
+	return e.Err
+}
+
 // An EnvironmentVariableResolver is a resolver specifically capable of adding
 // additional context in the form of a prefix to any loaded environment
 // variables
 type EnvironmentVariableResolver struct {
 	Prefix     string
 	Translator KeyTranslator
+
+	// Mapper, if set, maps the segments of each key, including the Prefix, to
+	// the name of the environment variable to lookup, in place of joining them
+	// with EnvSeparator and applying the Translator
+	Mapper KeyMapper
+
+	// FileIndirection toggles reading the value of an unset environment
+	// variable from the file named by the same variable with EnvFileSuffix
+	// appended, ie "DB_PASSWORD_FILE=/run/secrets/db". Trailing newlines are
+	// removed from the contents of the file, which is then parsed like the
+	// value of the variable itself.
+	FileIndirection bool
+
+	// MaxFileSize is the size, in bytes, of the largest file read via file
+	// indirection. If zero, DefaultMaxSecretSize is used.
+	MaxFileSize int64
+
+	// ListKeys are the keys whose values are split into lists of strings on
+	// the ListSeparator, ie "a,b,c". Leading and trailing whitespace is trimmed
+	// from each item and empty items are dropped, meaning "a" is resolved as a
+	// single item list. The values of other keys are never split.
+	ListKeys []string
+
+	// MapKeys are the keys whose values are split into ConfigMaps, by
+	// splitting them on the ListSeparator and each item on the
+	// KeyValueSeparator, ie "team=core,tier=1". Items without the
+	// KeyValueSeparator are mapped to an empty string.
+	MapKeys []string
+
+	// ListSeparator is used to split the values of ListKeys and MapKeys. If
+	// empty, EnvListSeparator is used.
+	ListSeparator string
+
+	// KeyValueSeparator is used to split the items of the values of MapKeys.
+	// If empty, EnvKeyValueSeparator is used.
+	KeyValueSeparator string
+
+	// ParseJSON toggles decoding values which are JSON encoded objects or
+	// arrays, ie `{"host": "localhost"}`. Values which are not valid JSON are
+	// returned unmodified.
+	ParseJSON bool
+
+	// Bindings holds explicit environment variable names for config keys,
+	// which are tried in order before the name derived from the key. Set it to
+	// Venom.Bindings to use the bindings added via BindEnv.
+	Bindings *EnvBindings
+
+	// IndexedKeys are the keys whose lists are reconstructed from indexed
+	// environment variables when the variable for the key is not set. For
+	// example, a lookup for "servers" will return a list of ConfigMaps, ordered
+	// by their index, from the variables "SERVERS_0_HOST" and "SERVERS_1_HOST",
+	// or a list of values from the variables "SERVERS_0" and "SERVERS_1". The
+	// keys of reconstructed ConfigMaps are lower case, and their values are
+	// only parsed if ParseJSON is set.
+	IndexedKeys []string
 }
 
 // Resolve is a Resolver implementation which attempts to load the requested
 // configuration from an environment variable
-func (r *EnvironmentVariableResolver) Resolve(keys []string, _ ConfigMap) (val interface{}, ok bool) {
+func (r *EnvironmentVariableResolver) Resolve(keys []string, config ConfigMap) (val interface{}, ok bool) {
+	val, ok, _ = r.ResolveErr(keys, config)
+	return val, ok
+}
+
+// ResolveErr is an ErrorResolver implementation which attempts to load the
+// requested configuration from an environment variable, returning an
+// EnvFileError if file indirection is enabled and the file named by the
+// environment variable can not be read
+func (r *EnvironmentVariableResolver) ResolveErr(keys []string, _ ConfigMap) (val interface{}, ok bool, err error) {
 	// copy the keys so we don't negatively impact subsequent lookups
 	keysCopy := make([]string, len(keys))
 	copy(keysCopy, keys)
@@ -34,18 +137,376 @@ func (r *EnvironmentVariableResolver) Resolve(keys []string, _ ConfigMap) (val i
 		keysCopy = append([]string{r.Prefix}, keysCopy...)
 	}
 
-	var translator KeyTranslator
-	if r.Translator == nil {
-		// If we weren't given a specific translator to use, then use the
-		// default translator.
-		translator = DefaultEnvironmentVariableKeyTranslator
+	var name string
+	if r.Mapper != nil {
+		name = r.Mapper(keysCopy)
 	} else {
-		// Otherwise, use the translator that was provided when this resolver
-		// was created.
-		translator = r.Translator
+		var translator KeyTranslator
+		if r.Translator == nil {
+			// If we weren't given a specific translator to use, then use the
+			// default translator.
+			translator = DefaultEnvironmentVariableKeyTranslator
+		} else {
+			// Otherwise, use the translator that was provided when this
+			// resolver was created.
+			translator = r.Translator
+		}
+		name = toEnvironmentVariable(keysCopy, translator)
+	}
+
+	key := strings.Join(keys, Delim)
+	candidates := append(r.Bindings.Names(key), name)
+	for _, candidate := range candidates {
+		if val, ok := os.LookupEnv(candidate); ok {
+			return r.parseValue(key, val), true, nil
+		}
 	}
 
-	return os.LookupEnv(toEnvironmentVariable(keysCopy, translator))
+	if r.FileIndirection {
+		for _, candidate := range candidates {
+			val, ok, err := r.resolveFile(candidate + EnvFileSuffix)
+			if err != nil {
+				return nil, false, err
+			} else if ok {
+				return r.parseValue(key, val), true, nil
+			}
+		}
+	}
+
+	if containsKey(r.IndexedKeys, key) {
+		if val, ok := r.resolveIndexed(name); ok {
+			return val, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// parseValue converts the value of the environment variable for the provided
+// key into a structured value, as configured by the resolver
+func (r *EnvironmentVariableResolver) parseValue(key, val string) interface{} {
+	if r.ParseJSON {
+		if parsed, ok := parseJSONValue(val); ok {
+			return parsed
+		}
+	}
+
+	switch {
+	case containsKey(r.MapKeys, key):
+		pairs := make(ConfigMap)
+		for _, item := range r.splitList(val) {
+			parts := strings.SplitN(item, r.keyValueSeparator(), 2)
+			if len(parts) == 1 {
+				parts = append(parts, "")
+			}
+			pairs[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+		return pairs
+	case containsKey(r.ListKeys, key):
+		items := r.splitList(val)
+		list := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			list = append(list, item)
+		}
+		return list
+	}
+	return val
+}
+
+// splitList splits the provided value on the ListSeparator, trimming leading
+// and trailing whitespace from each item and dropping empty items
+func (r *EnvironmentVariableResolver) splitList(val string) []string {
+	separator := r.ListSeparator
+	if separator == "" {
+		separator = EnvListSeparator
+	}
+
+	var items []string
+	for _, item := range strings.Split(val, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *EnvironmentVariableResolver) keyValueSeparator() string {
+	if r.KeyValueSeparator == "" {
+		return EnvKeyValueSeparator
+	}
+	return r.KeyValueSeparator
+}
+
+// containsKey returns true if keys contains the provided key
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSONValue decodes the provided value if it is a JSON encoded object or
+// array. Objects are returned as ConfigMaps.
+func parseJSONValue(val string) (interface{}, bool) {
+	trimmed := strings.TrimSpace(val)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var parsed interface{}
+	if err := dec.Decode(&parsed); err != nil || dec.More() {
+		return nil, false
+	}
+
+	parsed = convertJSONNumbers(parsed)
+	if object, ok := parsed.(map[string]interface{}); ok {
+		return make(ConfigMap).merge(object), true
+	}
+	return parsed, true
+}
+
+// resolveIndexed reconstructs a list from the environment variables named by
+// the provided name, followed by an index, ie "SERVERS_0_HOST"
+func (r *EnvironmentVariableResolver) resolveIndexed(name string) (interface{}, bool) {
+	prefix := name + EnvSeparator
+	var environ []string
+	for _, entry := range os.Environ() {
+		if strings.HasPrefix(entry, prefix) {
+			environ = append(environ, entry)
+		}
+	}
+	sort.Strings(environ)
+
+	items := make(map[int]interface{})
+	for _, entry := range environ {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], prefix) {
+			continue
+		}
+
+		keys := strings.Split(strings.TrimPrefix(parts[0], prefix), EnvSeparator)
+		index, err := strconv.Atoi(keys[0])
+		if err != nil || index < 0 || strconv.Itoa(index) != keys[0] {
+			continue
+		}
+
+		var val interface{} = parts[1]
+		if r.ParseJSON {
+			if parsed, ok := parseJSONValue(parts[1]); ok {
+				val = parsed
+			}
+		}
+		if len(keys) == 1 {
+			// nested variables, ie "SERVERS_0_HOST", take precedence over
+			// "SERVERS_0"
+			if _, ok := items[index].(ConfigMap); !ok {
+				items[index] = val
+			}
+			continue
+		}
+
+		item, ok := items[index].(ConfigMap)
+		if !ok {
+			item = make(ConfigMap)
+			items[index] = item
+		}
+		setEnvValue(item, lowerKeys(keys[1:]), val)
+	}
+
+	if len(items) == 0 {
+		return nil, false
+	}
+
+	indexes := make([]int, 0, len(items))
+	for index := range items {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	list := make([]interface{}, 0, len(indexes))
+	for _, index := range indexes {
+		list = append(list, items[index])
+	}
+	return list, true
+}
+
+// setEnvValue inserts the value of an environment variable into the nested
+// keyspace of data. Nested variables take precedence over conflicting values,
+// ie "DB_HOST" takes precedence over "DB", regardless of the order in which
+// they are inserted.
+func setEnvValue(data ConfigMap, keys []string, val interface{}) {
+	for index, key := range keys {
+		if index == len(keys)-1 {
+			if _, ok := data[key].(ConfigMap); !ok {
+				data[key] = val
+			}
+			return
+		}
+
+		nested, ok := data[key].(ConfigMap)
+		if !ok {
+			nested = make(ConfigMap)
+			data[key] = nested
+		}
+		data = nested
+	}
+}
+
+// lowerKeys converts each of the provided keys to lower case
+func lowerKeys(keys []string) []string {
+	lowered := make([]string, len(keys))
+	for index, key := range keys {
+		lowered[index] = strings.ToLower(key)
+	}
+	return lowered
+}
+
+// An EnvironmentSource returns a snapshot of environment variables in the
+// "key=value" form returned by os.Environ
+type EnvironmentSource func() []string
+
+// EnvironmentMap returns an EnvironmentSource which provides the variables of
+// the provided map, which is useful for loading a deterministic environment
+// in tests
+func EnvironmentMap(env map[string]string) EnvironmentSource {
+	return func() []string {
+		environ := make([]string, 0, len(env))
+		for name, value := range env {
+			environ = append(environ, name+"="+value)
+		}
+		return environ
+	}
+}
+
+// LoadEnvironment loads every environment variable with the provided prefix
+// into the specified ConfigLevel. See LoadEnvironmentFrom for more details.
+func (v *Venom) LoadEnvironment(prefix string, level ConfigLevel) error {
+	return v.LoadEnvironmentFrom(os.Environ, prefix, level)
+}
+
+// LoadEnvironmentFrom loads a snapshot of every environment variable provided
+// by src with the provided prefix into the specified ConfigLevel.
+//
+// Variable names are mapped to keys by reversing the default conventions of
+// the EnvironmentVariableResolver. The prefix and EnvSeparator are removed,
+// the remaining name is split on EnvSeparator to form nested keys, and each
+// key is converted to lower case, meaning "APP_DB_HOST" is loaded as "db.host"
+// for a prefix of "app". Values are loaded as strings. An empty prefix loads
+// every variable.
+//
+// Unlike an EnvironmentVariableResolver, the loaded values appear in Debug,
+// Level and WriteFile, and can be unmarshalled into nested structs. The data
+// should be loaded into a ConfigLevel without a custom Resolver, as a
+// Resolver, such as the one registered at the EnvironmentLevel by Default,
+// takes precedence over the data stored within its level.
+func (v *Venom) LoadEnvironmentFrom(src EnvironmentSource, prefix string, level ConfigLevel) error {
+	data := environmentData(src(), prefix)
+	if err := v.Limits().check(data); err != nil {
+		return asLoadError("environment", err)
+	}
+
+	v.mergeLayer(newFileLayer(level, fileLoad{}, data))
+	return nil
+}
+
+// environmentData builds a nested ConfigMap from the provided environment
+// variables with the provided prefix
+func environmentData(environ []string, prefix string) ConfigMap {
+	if prefix != "" {
+		prefix = toEnvironmentVariable([]string{prefix}, DefaultEnvironmentVariableKeyTranslator) + EnvSeparator
+	}
+
+	data := make(ConfigMap)
+	for _, entry := range environ {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], prefix) {
+			continue
+		}
+
+		keys := strings.Split(strings.TrimPrefix(parts[0], prefix), EnvSeparator)
+		valid := true
+		for _, key := range keys {
+			if key == "" {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			setEnvValue(data, lowerKeys(keys), parts[1])
+		}
+	}
+	return data
+}
+
+// EnvBindings holds explicit bindings of config keys to the names of the
+// environment variables which provide their values, for variables which do not
+// follow the naming conventions of the EnvironmentVariableResolver, ie
+// "DATABASE_URL" or "PGHOST". The zero value is ready to use.
+type EnvBindings struct {
+	mu    sync.RWMutex
+	names map[string][]string
+}
+
+// Bind binds the provided key to the provided environment variable names,
+// replacing any existing binding. The names are tried in order, and are not
+// modified by the Prefix or Translator of a resolver.
+func (b *EnvBindings) Bind(key string, names ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.names == nil {
+		b.names = make(map[string][]string)
+	}
+	b.names[key] = append([]string(nil), names...)
+}
+
+// Names returns a copy of the environment variable names bound to the
+// provided key
+func (b *EnvBindings) Names(key string) []string {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]string(nil), b.names[key]...)
+}
+
+// BindEnv binds the provided key to the provided environment variable names,
+// which are tried in order before the name derived from the key by every
+// EnvironmentVariableResolver whose Bindings are the Bindings of this Venom
+// instance, such as the resolver registered by Default. The bound names are
+// listed by Explain.
+func (v *Venom) BindEnv(key string, names ...string) {
+	v.envBindings.Bind(key, names...)
+}
+
+// Bindings returns the environment variable bindings added via BindEnv, which
+// can be used as the Bindings of an EnvironmentVariableResolver
+func (v *Venom) Bindings() *EnvBindings {
+	return &v.envBindings
+}
+
+// resolveFile reads the value of a config from the file named by the provided
+// environment variable
+func (r *EnvironmentVariableResolver) resolveFile(name string) (string, bool, error) {
+	path, ok := os.LookupEnv(name)
+	if !ok {
+		return "", false, nil
+	}
+
+	max := r.MaxFileSize
+	if max <= 0 {
+		max = DefaultMaxSecretSize
+	}
+
+	val, err := readSecretFile(path, max)
+	if err != nil {
+		return "", false, &EnvFileError{Name: name, Path: path, Err: err}
+	}
+	return val, true, nil
 }
 
 // The DefaultEnvironmentVariableKeyTranslator is the default KeyTranslator
@@ -58,15 +519,8 @@ func DefaultEnvironmentVariableKeyTranslator(b byte) byte {
 }
 
 func toEnvironmentVariable(keys []string, translator KeyTranslator) string {
-	// Convert the input keys into a single environment variable that we can
-	// perform a lookup on.
-	key := []byte(strings.Join(keys, EnvSeparator))
-
-	// Next, perform any custom translations performed by the KeyTranslator.
-	for index, char := range key {
-		key[index] = translator(char)
-	}
-
-	// Finally, return the translated key to the caller.
-	return string(key)
+	// Convert the input keys into a single environment variable, performing
+	// any custom translations of the KeyTranslator, that we can perform a
+	// lookup on.
+	return TranslatorMapper(EnvSeparator, translator)(keys)
 }
@@ -1,7 +1,10 @@
 package venom
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,7 +25,7 @@ func TestEnvironment(t *testing.T) {
 			key:      "foo",
 			envVar:   "FOO",
 			value:    "bar",
-			resolver: defaultEnvResolver,
+			resolver: &EnvironmentVariableResolver{},
 			expect:   "bar",
 			ok:       true,
 		},
@@ -73,3 +76,540 @@ func TestEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentFileIndirection(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"db":    "hunter2\n",
+		"large": "0123456789",
+		"list":  "a, b\n",
+	})
+
+	testIO := []struct {
+		tc       string
+		env      map[string]string
+		resolver *EnvironmentVariableResolver
+		expect   interface{}
+		ok       bool
+		err      error
+	}{
+		{
+			tc:       "should read the file named by the _FILE variable",
+			env:      map[string]string{"DB_PASSWORD_FILE": filepath.Join(dir, "db")},
+			resolver: &EnvironmentVariableResolver{FileIndirection: true},
+			expect:   "hunter2",
+			ok:       true,
+		},
+		{
+			tc: "should prefer the variable over the _FILE variable",
+			env: map[string]string{
+				"DB_PASSWORD":      "direct",
+				"DB_PASSWORD_FILE": filepath.Join(dir, "db"),
+			},
+			resolver: &EnvironmentVariableResolver{FileIndirection: true},
+			expect:   "direct",
+			ok:       true,
+		},
+		{
+			tc:       "should ignore the _FILE variable when disabled",
+			env:      map[string]string{"DB_PASSWORD_FILE": filepath.Join(dir, "db")},
+			resolver: &EnvironmentVariableResolver{},
+			expect:   "default",
+			ok:       true,
+		},
+		{
+			tc:       "should apply the prefix to the _FILE variable",
+			env:      map[string]string{"APP_DB_PASSWORD_FILE": filepath.Join(dir, "db")},
+			resolver: &EnvironmentVariableResolver{Prefix: "APP", FileIndirection: true},
+			expect:   "hunter2",
+			ok:       true,
+		},
+		{
+			tc:       "should parse the contents of the file",
+			env:      map[string]string{"DB_PASSWORD_FILE": filepath.Join(dir, "list")},
+			resolver: &EnvironmentVariableResolver{FileIndirection: true, ListKeys: []string{"db.password"}},
+			expect:   []interface{}{"a", "b"},
+			ok:       true,
+		},
+		{
+			tc:       "should error on missing files",
+			env:      map[string]string{"DB_PASSWORD_FILE": filepath.Join(dir, "missing")},
+			resolver: &EnvironmentVariableResolver{FileIndirection: true},
+			err: &EnvFileError{
+				Name: "DB_PASSWORD_FILE",
+				Path: filepath.Join(dir, "missing"),
+				Err:  &os.PathError{Op: "open", Path: filepath.Join(dir, "missing"), Err: syscall.ENOENT},
+			},
+		},
+		{
+			tc:       "should error on files larger than the max size",
+			env:      map[string]string{"DB_PASSWORD_FILE": filepath.Join(dir, "large")},
+			resolver: &EnvironmentVariableResolver{FileIndirection: true, MaxFileSize: 8},
+			err: &EnvFileError{
+				Name: "DB_PASSWORD_FILE",
+				Path: filepath.Join(dir, "large"),
+				Err:  &LimitError{Limit: LimitFileSize, Max: 8},
+			},
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			v := New()
+			v.SetDefault("db.password", "default")
+			v.RegisterResolver(EnvironmentLevel, test.resolver)
+
+			actual, ok, err := v.Lookup("db.password")
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expect, actual)
+
+			// Find treats errors as the key not being found at that level
+			if test.err != nil {
+				assert.Equal(t, "default", v.Get("db.password"))
+				e := v.Explain("db.password")
+				assert.Equal(t, EnvironmentLevel, e.Level)
+				assert.Equal(t, "db.password: "+test.err.Error()+" (level 2)", e.String())
+			}
+		})
+	}
+}
+
+func TestEnvironmentStructuredValues(t *testing.T) {
+	testIO := []struct {
+		tc       string
+		env      map[string]string
+		resolver *EnvironmentVariableResolver
+		key      string
+		expect   interface{}
+		ok       bool
+	}{
+		{
+			tc:       "should return raw strings by default",
+			env:      map[string]string{"ALLOWED_HOSTS": "a,b,c"},
+			resolver: &EnvironmentVariableResolver{},
+			key:      "allowed.hosts",
+			expect:   "a,b,c",
+			ok:       true,
+		},
+		{
+			tc:       "should split delimited lists",
+			env:      map[string]string{"ALLOWED_HOSTS": "a, b,,c"},
+			resolver: &EnvironmentVariableResolver{ListKeys: []string{"allowed.hosts"}},
+			key:      "allowed.hosts",
+			expect:   []interface{}{"a", "b", "c"},
+			ok:       true,
+		},
+		{
+			tc:       "should split single item lists",
+			env:      map[string]string{"ALLOWED_HOSTS": "a"},
+			resolver: &EnvironmentVariableResolver{ListKeys: []string{"allowed.hosts"}},
+			key:      "allowed.hosts",
+			expect:   []interface{}{"a"},
+			ok:       true,
+		},
+		{
+			tc:       "should split lists on a custom separator",
+			env:      map[string]string{"ALLOWED_HOSTS": "a;b"},
+			resolver: &EnvironmentVariableResolver{ListKeys: []string{"allowed.hosts"}, ListSeparator: ";"},
+			key:      "allowed.hosts",
+			expect:   []interface{}{"a", "b"},
+			ok:       true,
+		},
+		{
+			tc:       "should not split the values of other keys",
+			env:      map[string]string{"DB_URL": "postgres://h/db?opts=a,b"},
+			resolver: &EnvironmentVariableResolver{ListKeys: []string{"allowed.hosts"}, MapKeys: []string{"labels"}},
+			key:      "db.url",
+			expect:   "postgres://h/db?opts=a,b",
+			ok:       true,
+		},
+		{
+			tc:       "should parse key value maps",
+			env:      map[string]string{"LABELS": "team=core, tier = 1"},
+			resolver: &EnvironmentVariableResolver{MapKeys: []string{"labels"}},
+			key:      "labels",
+			expect:   ConfigMap{"team": "core", "tier": "1"},
+			ok:       true,
+		},
+		{
+			tc:       "should map items without a value to empty strings",
+			env:      map[string]string{"LABELS": "team:core;canary"},
+			resolver: &EnvironmentVariableResolver{MapKeys: []string{"labels"}, ListSeparator: ";", KeyValueSeparator: ":"},
+			key:      "labels",
+			expect:   ConfigMap{"team": "core", "canary": ""},
+			ok:       true,
+		},
+		{
+			tc:  "should split the values of bound variables",
+			env: map[string]string{"HOSTS": "a,b"},
+			resolver: func() *EnvironmentVariableResolver {
+				r := &EnvironmentVariableResolver{ListKeys: []string{"allowed.hosts"}, Bindings: &EnvBindings{}}
+				r.Bindings.Bind("allowed.hosts", "HOSTS")
+				return r
+			}(),
+			key:    "allowed.hosts",
+			expect: []interface{}{"a", "b"},
+			ok:     true,
+		},
+		{
+			tc:       "should parse JSON objects",
+			env:      map[string]string{"DB": `{"host": "localhost", "port": 5432, "opts": {"ssl": true}}`},
+			resolver: &EnvironmentVariableResolver{ParseJSON: true, ListKeys: []string{"db"}},
+			key:      "db",
+			expect:   ConfigMap{"host": "localhost", "port": int64(5432), "opts": ConfigMap{"ssl": true}},
+			ok:       true,
+		},
+		{
+			tc:       "should parse JSON arrays",
+			env:      map[string]string{"PORTS": `[80, 443]`},
+			resolver: &EnvironmentVariableResolver{ParseJSON: true},
+			key:      "ports",
+			expect:   []interface{}{int64(80), int64(443)},
+			ok:       true,
+		},
+		{
+			tc:       "should return invalid JSON unmodified",
+			env:      map[string]string{"PORTS": `[80, 443`},
+			resolver: &EnvironmentVariableResolver{ParseJSON: true},
+			key:      "ports",
+			expect:   `[80, 443`,
+			ok:       true,
+		},
+		{
+			tc: "should reconstruct lists of objects from indexed variables",
+			env: map[string]string{
+				"APP_SERVERS_0_HOST":     "a.example.com",
+				"APP_SERVERS_0_PORT":     "80",
+				"APP_SERVERS_1_HOST":     "b.example.com",
+				"APP_SERVERS_1_TLS_CERT": "/etc/b.pem",
+				"APP_SERVERS_10_HOST":    "k.example.com",
+				"APP_SERVERS_X_HOST":     "ignored",
+			},
+			resolver: &EnvironmentVariableResolver{Prefix: "app", IndexedKeys: []string{"servers"}},
+			key:      "servers",
+			expect: []interface{}{
+				ConfigMap{"host": "a.example.com", "port": "80"},
+				ConfigMap{"host": "b.example.com", "tls": ConfigMap{"cert": "/etc/b.pem"}},
+				ConfigMap{"host": "k.example.com"},
+			},
+			ok: true,
+		},
+		{
+			tc: "should reconstruct lists of values from indexed variables",
+			env: map[string]string{
+				"HOSTS_0": "a",
+				"HOSTS_1": "b,c",
+				"HOSTS_2": "[1]",
+			},
+			resolver: &EnvironmentVariableResolver{IndexedKeys: []string{"hosts"}, ListKeys: []string{"hosts"}, ParseJSON: true},
+			key:      "hosts",
+			expect:   []interface{}{"a", "b,c", []interface{}{int64(1)}},
+			ok:       true,
+		},
+		{
+			tc: "should prefer the unindexed variable",
+			env: map[string]string{
+				"HOSTS":   "a,b",
+				"HOSTS_0": "c",
+			},
+			resolver: &EnvironmentVariableResolver{IndexedKeys: []string{"hosts"}},
+			key:      "hosts",
+			expect:   "a,b",
+			ok:       true,
+		},
+		{
+			tc:       "should not resolve missing indexed variables",
+			env:      map[string]string{"HOSTNAME_0": "a"},
+			resolver: &EnvironmentVariableResolver{IndexedKeys: []string{"hosts"}},
+			key:      "hosts",
+		},
+		{
+			tc:       "should only reconstruct lists for indexed keys",
+			env:      map[string]string{"HOSTS_0": "a"},
+			resolver: &EnvironmentVariableResolver{IndexedKeys: []string{"servers"}},
+			key:      "hosts",
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			v := New()
+			v.RegisterResolver(EnvironmentLevel, test.resolver)
+
+			actual, ok := v.Find(test.key)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
+
+func TestEnvironmentListUnmarshal(t *testing.T) {
+	t.Setenv("ALLOWED_HOSTS", "a")
+	t.Setenv("DB_URL", "postgres://h/db?opts=a,b")
+
+	v := New()
+	v.RegisterResolver(EnvironmentLevel, &EnvironmentVariableResolver{ListKeys: []string{"allowed.hosts"}})
+
+	var config struct {
+		Allowed struct {
+			Hosts []string
+		}
+		DB struct {
+			URL string
+		}
+	}
+	assert.NoError(t, Unmarshal(v, &config))
+	assert.Equal(t, []string{"a"}, config.Allowed.Hosts)
+	assert.Equal(t, "postgres://h/db?opts=a,b", config.DB.URL)
+	assert.Equal(t, "postgres://h/db?opts=a,b", v.GetString("db.url"))
+}
+
+func TestEnvironmentIndexedUnmarshal(t *testing.T) {
+	t.Setenv("SERVERS_0_HOST", "a")
+	t.Setenv("SERVERS_0_PORT", "80")
+	t.Setenv("SERVERS_1_HOST", "b")
+	t.Setenv("SERVERS_1_TLS_CERT", "/etc/b.pem")
+
+	v := New()
+	v.RegisterResolver(EnvironmentLevel, &EnvironmentVariableResolver{IndexedKeys: []string{"servers"}})
+
+	type server struct {
+		Host string
+		Port string
+		TLS  struct {
+			Cert string
+		}
+	}
+	var config struct {
+		Servers []server
+	}
+	assert.NoError(t, Unmarshal(v, &config))
+
+	expect := []server{{Host: "a", Port: "80"}, {Host: "b"}}
+	expect[1].TLS.Cert = "/etc/b.pem"
+	assert.Equal(t, expect, config.Servers)
+
+	t.Run("should error on items which are not maps", func(t *testing.T) {
+		t.Setenv("SERVERS_2", "c")
+		var config struct {
+			Servers []server
+		}
+		var coerceErr *CoerceErr
+		assert.True(t, errors.As(Unmarshal(v, &config), &coerceErr))
+	})
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	env := EnvironmentMap(map[string]string{
+		"APP_DB_HOST":        "localhost",
+		"APP_DB_PORT":        "5432",
+		"APP_DB":             "ignored in favor of nested keys",
+		"APP_LOG_LEVEL":      "debug",
+		"APP_NAME":           "venom",
+		"APP__INVALID":       "skipped",
+		"APP_TRAILING_":      "skipped",
+		"APPLICATION_NAME":   "not prefixed",
+		"OTHER_SERVICE_NAME": "not prefixed",
+	})
+
+	testIO := []struct {
+		tc     string
+		prefix string
+		expect ConfigMap
+	}{
+		{
+			tc:     "should load prefixed variables",
+			prefix: "app",
+			expect: ConfigMap{
+				"db":   ConfigMap{"host": "localhost", "port": "5432"},
+				"log":  ConfigMap{"level": "debug"},
+				"name": "venom",
+			},
+		},
+		{
+			tc:     "should load every variable without a prefix",
+			prefix: "",
+			expect: ConfigMap{
+				"app": ConfigMap{
+					"db":   ConfigMap{"host": "localhost", "port": "5432"},
+					"log":  ConfigMap{"level": "debug"},
+					"name": "venom",
+				},
+				"application": ConfigMap{"name": "not prefixed"},
+				"other":       ConfigMap{"service": ConfigMap{"name": "not prefixed"}},
+			},
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			v := New()
+			assert.NoError(t, v.LoadEnvironmentFrom(env, test.prefix, EnvironmentLevel))
+			assert.Equal(t, test.expect, v.Level(EnvironmentLevel))
+		})
+	}
+}
+
+func TestLoadEnvironmentUnmarshal(t *testing.T) {
+	t.Setenv("VENOM_TEST_DB_HOST", "localhost")
+	t.Setenv("VENOM_TEST_DB_USER", "admin")
+
+	v := New()
+	v.SetDefault("db.user", "root")
+	assert.NoError(t, v.LoadEnvironment("venom_test", EnvironmentLevel))
+
+	var config struct {
+		DB struct {
+			Host string
+			User string
+		} `venom:"db"`
+	}
+	assert.NoError(t, Unmarshal(v, &config))
+	assert.Equal(t, "localhost", config.DB.Host)
+	assert.Equal(t, "admin", config.DB.User)
+	assert.Contains(t, v.Debug(), `"host": "localhost"`)
+}
+
+func TestLoadEnvironmentLimits(t *testing.T) {
+	v := New()
+	v.SetLimits(LoadLimits{MaxKeys: 1})
+	err := v.LoadEnvironmentFrom(EnvironmentMap(map[string]string{
+		"APP_A": "1",
+		"APP_B": "2",
+	}), "app", EnvironmentLevel)
+
+	var limitErr *LimitError
+	assert.True(t, errors.As(err, &limitErr))
+	assert.Nil(t, v.Level(EnvironmentLevel))
+}
+
+func TestBindEnv(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"pghost": "from-file\n"})
+
+	testIO := []struct {
+		tc       string
+		env      map[string]string
+		names    []string
+		resolver *EnvironmentVariableResolver
+		expect   interface{}
+		ok       bool
+	}{
+		{
+			tc:       "should resolve the first bound name",
+			env:      map[string]string{"DATABASE_URL": "postgres://url", "PGHOST": "pghost", "DB_HOST": "derived"},
+			names:    []string{"DATABASE_URL", "PGHOST"},
+			resolver: &EnvironmentVariableResolver{},
+			expect:   "postgres://url",
+			ok:       true,
+		},
+		{
+			tc:       "should try each bound name in order",
+			env:      map[string]string{"PGHOST": "pghost", "DB_HOST": "derived"},
+			names:    []string{"DATABASE_URL", "PGHOST"},
+			resolver: &EnvironmentVariableResolver{},
+			expect:   "pghost",
+			ok:       true,
+		},
+		{
+			tc:       "should fall back to the derived name",
+			env:      map[string]string{"DB_HOST": "derived"},
+			names:    []string{"DATABASE_URL", "PGHOST"},
+			resolver: &EnvironmentVariableResolver{},
+			expect:   "derived",
+			ok:       true,
+		},
+		{
+			tc:       "should not apply the prefix to bound names",
+			env:      map[string]string{"PGHOST": "pghost", "APP_PGHOST": "prefixed"},
+			names:    []string{"PGHOST"},
+			resolver: &EnvironmentVariableResolver{Prefix: "app"},
+			expect:   "pghost",
+			ok:       true,
+		},
+		{
+			tc:       "should read the _FILE variable of bound names",
+			env:      map[string]string{"PGHOST_FILE": filepath.Join(dir, "pghost")},
+			names:    []string{"PGHOST"},
+			resolver: &EnvironmentVariableResolver{FileIndirection: true},
+			expect:   "from-file",
+			ok:       true,
+		},
+		{
+			tc:       "should only use the bindings of the resolver",
+			env:      map[string]string{"PGHOST": "pghost"},
+			names:    []string{"PGHOST"},
+			resolver: &EnvironmentVariableResolver{Bindings: &EnvBindings{}},
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			v := New()
+			if test.resolver.Bindings == nil {
+				test.resolver.Bindings = v.Bindings()
+			}
+			v.RegisterResolver(EnvironmentLevel, test.resolver)
+			v.BindEnv("db.host", test.names...)
+
+			actual, ok := v.Find("db.host")
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
+
+func TestBindEnvRegisteredResolver(t *testing.T) {
+	t.Setenv("PGHOST", "pghost")
+	t.Setenv("APP_DB_HOST", "prefixed")
+
+	r := &EnvironmentVariableResolver{}
+	v := New()
+	v.RegisterResolver(EnvironmentLevel, r)
+	v.BindEnv("db.host", "PGHOST")
+
+	// resolvers are registered as is, so they only use the bindings of venom
+	// once they are wired in
+	_, ok := v.Find("db.host")
+	assert.False(t, ok)
+
+	r.Prefix = "app"
+	assert.Equal(t, "prefixed", v.Get("db.host"))
+
+	r.Bindings = v.Bindings()
+	assert.Equal(t, "pghost", v.Get("db.host"))
+}
+
+func TestBindEnvExplain(t *testing.T) {
+	t.Setenv("PGHOST", "pghost")
+
+	v := Default()
+	v.BindEnv("db.host", "DATABASE_URL", "PGHOST")
+	v.Alias("database.host", "db.host")
+
+	e := v.Explain("database.host")
+	assert.Equal(t, []string{"DATABASE_URL", "PGHOST"}, e.BoundEnv)
+	assert.Equal(t, "database.host = pghost (level 2, alias of db.host, env DATABASE_URL, PGHOST)", e.String())
+
+	v.BindEnv("db.user", "PGUSER")
+	assert.Equal(t, "db.user: not found (env PGUSER)", v.Explain("db.user").String())
+
+	// bindings are per instance
+	assert.Nil(t, New().Explain("db.host").BoundEnv)
+	t.Setenv("PGHOST", "other")
+	assert.Equal(t, "other", v.Get("db.host"))
+	assert.Nil(t, Default().Get("db.host"))
+}
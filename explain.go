@@ -0,0 +1,112 @@
+package venom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// An Explanation describes where the value of a config key was resolved from
+type Explanation struct {
+	// Key is the key that was requested
+	Key string
+
+	// Resolved is the key that was actually searched for, which differs from
+	// Key if Key is an alias
+	Resolved string
+
+	// Value is the resolved value of the key
+	Value interface{}
+
+	// Found indicates whether or not the key was found
+	Found bool
+
+	// Level is the ConfigLevel the value was resolved from
+	Level ConfigLevel
+
+	// File is the path of the config file which provided the value, if the
+	// value was loaded from a file
+	File string
+
+	// Profile is the profile of the config file which provided the value, if
+	// that file was a profile overlay
+	Profile string
+
+	// BoundEnv lists the environment variable names bound to the resolved key
+	// via BindEnv, in the order they are tried
+	BoundEnv []string
+
+	// Err is the error reported by an ErrorResolver at Level, which stopped
+	// the search for the key
+	Err error
+}
+
+// String returns a human readable description of the Explanation
+func (e Explanation) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s (level %d%s)", e.Key, e.Err, e.Level, e.boundEnv(", "))
+	}
+	if !e.Found {
+		if len(e.BoundEnv) > 0 {
+			return fmt.Sprintf("%s: not found (%s)", e.Key, e.boundEnv(""))
+		}
+		return fmt.Sprintf("%s: not found", e.Key)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s = %v (level %d", e.Key, e.Value, e.Level)
+	if e.Resolved != e.Key {
+		fmt.Fprintf(&b, ", alias of %s", e.Resolved)
+	}
+	if e.File != "" {
+		fmt.Fprintf(&b, ", file %s", e.File)
+	}
+	if e.Profile != "" {
+		fmt.Fprintf(&b, ", profile %s", e.Profile)
+	}
+	b.WriteString(e.boundEnv(", "))
+	b.WriteString(")")
+	return b.String()
+}
+
+// boundEnv describes the bound environment variable names of the Explanation,
+// preceded by sep, or returns an empty string if there are none
+func (e Explanation) boundEnv(sep string) string {
+	if len(e.BoundEnv) == 0 {
+		return ""
+	}
+	return sep + "env " + strings.Join(e.BoundEnv, ", ")
+}
+
+// Explain searches for the given key in the same manner as Find, returning an
+// Explanation of where the value was resolved from. If the value was loaded
+// from a config file, the Explanation identifies the file, and profile, which
+// took precedence. Any environment variable names bound to the key via BindEnv
+// are also listed.
+func (v *Venom) Explain(key string) Explanation {
+	e := explainKey(v.Store, key)
+	if _, ok := v.Store.(Explainer); ok && e.Found {
+		v.files.explain(&e)
+	}
+	e.BoundEnv = v.envBindings.Names(e.Resolved)
+	return e
+}
+
+// explain populates the File and Profile of the provided Explanation with the
+// loaded file of the highest precedence which contains the resolved key
+func (f *fileLayers) explain(e *Explanation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := strings.Split(e.Resolved, Delim)
+	layers := f.sorted()
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].level != e.Level {
+			continue
+		}
+		if _, ok := defaultResolver.Resolve(keys, layers[i].data); ok {
+			e.File = layers[i].name
+			e.Profile = layers[i].profile
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package venom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	v := New()
+	v.SetDefault("log.level", "info")
+	v.SetDefault("foo", "default")
+	assert.NoError(t, v.LoadFile("testdata/layered/10-base.json"))
+	assert.NoError(t, v.LoadFile("testdata/layered/90-local.json"))
+	v.SetOverride("verbose", true)
+	v.Alias("debug", "verbose")
+
+	testIO := []struct {
+		key    string
+		expect Explanation
+		str    string
+	}{
+		{
+			key: "log.level",
+			expect: Explanation{
+				Key:      "log.level",
+				Resolved: "log.level",
+				Value:    "debug",
+				Found:    true,
+				Level:    FileLevel,
+				File:     "testdata/layered/90-local.json",
+			},
+			str: "log.level = debug (level 1, file testdata/layered/90-local.json)",
+		},
+		{
+			key: "log.file",
+			expect: Explanation{
+				Key:      "log.file",
+				Resolved: "log.file",
+				Value:    "/var/log/app.log",
+				Found:    true,
+				Level:    FileLevel,
+				File:     "testdata/layered/10-base.json",
+			},
+			str: "log.file = /var/log/app.log (level 1, file testdata/layered/10-base.json)",
+		},
+		{
+			key: "debug",
+			expect: Explanation{
+				Key:      "debug",
+				Resolved: "verbose",
+				Value:    true,
+				Found:    true,
+				Level:    OverrideLevel,
+			},
+			str: "debug = true (level 99, alias of verbose)",
+		},
+		{
+			key: "missing",
+			expect: Explanation{
+				Key:      "missing",
+				Resolved: "missing",
+			},
+			str: "missing: not found",
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.key, func(t *testing.T) {
+			e := v.Explain(test.key)
+			assert.Equal(t, test.expect, e)
+			assert.Equal(t, test.str, e.String())
+		})
+	}
+}
+
+func TestExplainStores(t *testing.T) {
+	for name, v := range map[string]*Venom{
+		"SafeConfigStore":     NewSafe(),
+		"LoggableConfigStore": NewLoggableWith(&TestLogger{}),
+		"SubscriptionStore": func() *Venom {
+			store, _ := NewSubscriptionStore(NewDefaultConfigStore())
+			return NewWithStore(store)
+		}(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			v.SetDefault("foo", "bar")
+			v.SetLevel(FileLevel, "foo", "baz")
+			e := v.Explain("foo")
+			assert.Equal(t, FileLevel, e.Level)
+			assert.Equal(t, "baz", e.Value)
+		})
+	}
+}
+
+func TestExplainMinimalStore(t *testing.T) {
+	v := NewWithStore(minimalStore{NewDefaultConfigStore()})
+	v.SetLevel(FileLevel, "foo", "bar")
+	v.BindEnv("foo", "FOO")
+
+	expect := Explanation{
+		Key:      "foo",
+		Resolved: "foo",
+		Value:    "bar",
+		Found:    true,
+		BoundEnv: []string{"FOO"},
+	}
+	assert.Equal(t, expect, v.Explain("foo"))
+
+	val, ok, err := v.Lookup("foo")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "bar", val)
+}
@@ -1,12 +1,12 @@
 package venom
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
-	"sort"
+	"io/fs"
+	"io/ioutil"
 	"strings"
 )
 
@@ -17,12 +17,31 @@ const (
 // extensionMap is the collection of file extensions to the IOFileLoaders that
 // can load files with the associated extensions
 var extensionMap = map[string]IOFileLoader{
-	jsonKey: JSONLoader,
+	jsonKey:  JSONLoader,
+	jsoncKey: JSONCLoader,
+	json5Key: JSONCLoader,
+	xmlKey:   XMLLoader,
 }
 
-// RegisterExtension registers an IOFileLoader for the provided file extension
+// RegisterExtension registers an IOFileLoader for the provided file extension.
+// Extensions are matched case-insensitively.
 func RegisterExtension(ext string, loader IOFileLoader) {
-	extensionMap[ext] = loader
+	extensionMap[normalizeExtension(ext)] = loader
+}
+
+// normalizeExtension strips any leading "." from the provided extension and
+// converts it to lower case
+func normalizeExtension(ext string) string {
+	return strings.ToLower(strings.TrimLeft(ext, "."))
+}
+
+// hasLoader returns true if an IOFileLoader is registered for the extension of
+// the provided file name, ignoring any outer extensions with a registered
+// IOFileDecoder
+func hasLoader(name string) bool {
+	format, _ := fileFormat(name)
+	_, ok := extensionMap[format]
+	return ok
 }
 
 // ErrNoFileLoader is the error returned when a file is attempted to be loaded
@@ -41,69 +60,329 @@ func (e ErrNoFileLoader) Error() string {
 // io.Reader into a map[string]interface{}
 type IOFileLoader func(io.Reader) (map[string]interface{}, error)
 
+var defaultJSONDecoder = &JSONDecoder{}
+
+// A JSONNumberMode determines how a JSONDecoder represents decoded numbers
+type JSONNumberMode int
+
+const (
+	// JSONNumbersAsFloat64 decodes all numbers as float64 values, which is the
+	// default behavior of encoding/json
+	JSONNumbersAsFloat64 JSONNumberMode = iota
+
+	// JSONNumbersAsNumber decodes all numbers as json.Number values, which
+	// retain the exact textual representation of the number
+	JSONNumbersAsNumber
+
+	// JSONNumbersAsInt64 decodes integral numbers as int64 values and all other
+	// numbers as float64 values. Integers which overflow an int64 are retained
+	// as json.Number values.
+	JSONNumbersAsInt64
+)
+
+// A JSONDecoder is capable of decoding JSON config data, with control over how
+// numbers are represented in the resulting config
+type JSONDecoder struct {
+	Numbers JSONNumberMode
+}
+
 // JSONLoader is an IOFileLoader which loads JSON config data
 func JSONLoader(r io.Reader) (map[string]interface{}, error) {
-	data := make(map[string]interface{})
-	if err := json.NewDecoder(r).Decode(&data); err != nil {
+	return defaultJSONDecoder.Load(r)
+}
+
+// Load is an IOFileLoader which decodes the JSON document read from r
+func (d *JSONDecoder) Load(r io.Reader) (map[string]interface{}, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkJSONLimits(input, limitsOf(r)); err != nil {
 		return nil, err
 	}
+
+	dec := json.NewDecoder(bytes.NewReader(input))
+	if d.Numbers != JSONNumbersAsFloat64 {
+		dec.UseNumber()
+	}
+
+	data := make(map[string]interface{})
+	if err := dec.Decode(&data); err != nil {
+		return nil, jsonLoadError(jsonKey, input, err, func(offset int64) int {
+			return int(offset) - 1
+		})
+	}
+
+	if d.Numbers == JSONNumbersAsInt64 {
+		convertJSONNumbers(data)
+	}
 	return data, nil
 }
 
+// checkJSONLimits returns a LimitError if the provided JSON document exceeds
+// any of the limits on the shape of config data. The document is scanned token
+// by token before it is decoded, so that the limits are enforced without first
+// allocating the whole document. Syntax errors are left to be reported when the
+// document is decoded.
+func checkJSONLimits(input []byte, limits LoadLimits) error {
+	if !limits.shaped() {
+		return nil
+	}
+
+	// a jsonFrame is an object or array which is being scanned
+	type jsonFrame struct {
+		key   string
+		array bool
+		index int
+		child string
+		named bool
+	}
+
+	counter := &limitCounter{limits: limits}
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.UseNumber()
+	var stack []*jsonFrame
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		var key string
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			switch {
+			case top.array:
+				key = fmt.Sprintf("%s[%d]", top.key, top.index)
+				top.index++
+			case !top.named:
+				name, _ := tok.(string)
+				top.child, top.named = childKey(top.key, name), true
+				if err := counter.key(top.child, name); err != nil {
+					return err
+				}
+				continue
+			default:
+				key, top.named = top.child, false
+			}
+		}
+
+		switch actual := tok.(type) {
+		case json.Delim:
+			if err := counter.nested(key, len(stack)+1); err != nil {
+				return err
+			}
+			stack = append(stack, &jsonFrame{key: key, array: actual == '['})
+		case string:
+			if err := counter.str(key, actual); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// convertJSONNumbers replaces all json.Number values nested within val with
+// either an int64, if the number is integral, or a float64
+func convertJSONNumbers(val interface{}) interface{} {
+	switch actual := val.(type) {
+	case map[string]interface{}:
+		for key, item := range actual {
+			actual[key] = convertJSONNumbers(item)
+		}
+	case []interface{}:
+		for index, item := range actual {
+			actual[index] = convertJSONNumbers(item)
+		}
+	case json.Number:
+		if !strings.ContainsAny(string(actual), ".eE") {
+			if n, err := actual.Int64(); err == nil {
+				return n
+			}
+			return actual
+		}
+		if f, err := actual.Float64(); err == nil {
+			return f
+		}
+	}
+	return val
+}
+
 // LoadFile loads the file from the provided path into Venoms configs. If the
 // file can't be opened, if no loader for the files extension exists, or if
 // loading the file fails, an error is returned
 func (v *Venom) LoadFile(name string) error {
-	file, err := os.Open(name)
+	return v.LoadFileAt(FileLevel, name)
+}
+
+// LoadFileAt loads the file from the provided path into the specified
+// ConfigLevel. The file is loaded into its own sub-level of that ConfigLevel,
+// as determined by FileSubLevel.
+//
+// If any profiles are active, overlay files for those profiles are loaded
+// above the file. See Profiles for more details.
+//
+// Any files referenced by IncludeDirective or ExtendsDirective keys within the
+// file are loaded beneath it.
+func (v *Venom) LoadFileAt(level ConfigLevel, name string) error {
+	return v.loadSource(&fileSource{name: name, at: level})
+}
+
+// decode loads config data from the provided io.Reader using the IOFileLoader
+// registered for the provided format, enforcing the LoadLimits set via
+// SetLimits. Formats may include the extensions of IOFileDecoders, ie
+// "json.gz", which are applied before the data is loaded.
+func (v *Venom) decode(r io.Reader, format string) (map[string]interface{}, error) {
+	ext, encodings := fileFormat("." + strings.TrimLeft(format, "."))
+	return decodeEncoded(r, ext, encodings, v.Limits())
+}
+
+// decodeFile loads the config data of the named file from the provided
+// io.Reader, as described by decode
+func (v *Venom) decodeFile(r io.Reader, name string) (map[string]interface{}, error) {
+	ext, encodings := fileFormat(name)
+	data, err := decodeEncoded(r, ext, encodings, v.Limits())
 	if err != nil {
-		return err
+		return nil, withPath(err, name)
 	}
+	return data, nil
+}
 
-	ext := strings.TrimLeft(filepath.Ext(name), ".")
+// decodeEncoded decodes the data read from r with the IOFileDecoders for the
+// provided encodings, in order, before loading it with the IOFileLoader for
+// the provided extension, enforcing the provided limits
+func decodeEncoded(r io.Reader, ext string, encodings []string, limits LoadLimits) (map[string]interface{}, error) {
 	loader, ok := extensionMap[ext]
 	if !ok {
-		return ErrNoFileLoader{ext}
+		return nil, ErrNoFileLoader{ext}
+	}
+
+	if len(encodings) > 0 {
+		// limit the encoded data as well as the decoded data, as decoders such
+		// as the AESGCMDecoder read all of their input before decoding it
+		r, _ = LoadLimits{MaxFileSize: limits.MaxFileSize}.limitReader(r)
+	}
+
+	for _, encoding := range encodings {
+		decoded, err := encodingMap[encoding](r)
+		if err != nil {
+			return nil, asLoadError(encoding, err)
+		}
+		r = decoded
+	}
+
+	r, limited := limits.limitReader(r)
+	data, err := loader(r)
+	if limited != nil && limited.exceeded {
+		// report exceeding the size limit even if the loader did not return
+		// the error of the reader
+		err = limited.err()
+	}
+	if err == nil {
+		err = limits.check(data)
+	}
+	if err != nil {
+		return nil, asLoadError(ext, err)
+	}
+	return data, nil
+}
+
+// LoadReader loads config data from the provided io.Reader into the specified
+// ConfigLevel, using the IOFileLoader registered for the provided format. The
+// format is a file extension, such as "json", with or without a leading ".".
+func (v *Venom) LoadReader(r io.Reader, format string, level ConfigLevel) error {
+	data, err := v.decode(r, format)
+	if err != nil {
+		return err
 	}
 
-	data, err := loader(file)
+	v.mergeLayer(newFileLayer(level, fileLoad{}, data))
+	return nil
+}
+
+// LoadBytes loads the provided config data into the specified ConfigLevel,
+// using the IOFileLoader registered for the provided format
+func (v *Venom) LoadBytes(b []byte, format string, level ConfigLevel) error {
+	return v.LoadReader(bytes.NewReader(b), format, level)
+}
+
+// LoadFS loads the file at the provided path within fsys into Venoms configs.
+// This allows configs to be loaded from sources such as an embed.FS or an
+// in-memory fstest.MapFS using the same IOFileLoaders as LoadFile.
+func (v *Venom) LoadFS(fsys fs.FS, name string) error {
+	data, err := v.readFileFS(fsys, name)
 	if err != nil {
 		return err
 	}
 
-	v.Merge(FileLevel, data)
+	v.mergeLayer(newFileLayer(FileLevel, fileLoad{name: name, base: name}, data))
 	return nil
 }
 
-func findFiles(dir string, recurse bool) (files sort.StringSlice) {
-	files = sort.StringSlice{}
+// readFileFS loads the config data from the file at the provided path within
+// fsys, verifying its signature if signature verification is enabled
+func (v *Venom) readFileFS(fsys fs.FS, name string) (map[string]interface{}, error) {
+	if v.signatures.enabled() {
+		return v.readSignedFile(name, func(name string) (io.ReadCloser, error) {
+			return fsys.Open(name)
+		})
+	}
 
-	path, _ := filepath.Abs(dir)
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return v.decodeFile(file, name)
+}
 
-	walk := func(file string, i os.FileInfo, err error) error {
+// findFilesFS returns the lexically ordered paths of all files within the dir
+// of fsys which have a registered IOFileLoader, optionally recursing into any
+// sub-directories
+func findFilesFS(fsys fs.FS, dir string, recurse bool) ([]string, error) {
+	var files []string
+	walk := func(file string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		for extension := range extensionMap {
-			if !i.IsDir() && strings.HasSuffix(file, extension) {
-				files = append(files, strings.Replace(file, "\\", "/", -1))
-			} else if i.IsDir() && !recurse && path != file {
+		if d.IsDir() {
+			if !recurse && file != dir {
 				// don't recurse into subdirectories
-				return filepath.SkipDir
+				return fs.SkipDir
 			}
+			return nil
+		}
+
+		if hasLoader(file) {
+			files = append(files, file)
 		}
 		return nil
 	}
-	_ = filepath.Walk(path, walk)
-	return
+
+	if err := fs.WalkDir(fsys, dir, walk); err != nil {
+		return nil, err
+	}
+	return files, nil
 }
 
-// LoadDirectory loads any config files found in the provided directory,
-// optionally recursing into any sub-directories
-func (v *Venom) LoadDirectory(dir string, recurse bool) error {
-	configFiles := findFiles(dir, recurse)
+// LoadDirectoryFS loads any config files found in the provided directory of
+// fsys, optionally recursing into any sub-directories
+func (v *Venom) LoadDirectoryFS(fsys fs.FS, dir string, recurse bool) error {
+	configFiles, err := findFilesFS(fsys, dir, recurse)
+	if err != nil {
+		return err
+	}
+
+	sortBySubLevel(configFiles)
 	for _, file := range configFiles {
-		if err := v.LoadFile(file); err != nil {
+		if err := v.LoadFS(fsys, file); err != nil {
 			return err
 		}
 	}
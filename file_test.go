@@ -2,10 +2,15 @@ package venom
 
 import (
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,14 +36,32 @@ func TestRegisterExtension(t *testing.T) {
 	}
 }
 
-func getJSONFileErr(file string) error {
+// getJSONFileErr returns the LoadError expected when loading the provided
+// invalid JSON file, which is reported as being loaded from path
+func getJSONFileErr(file, path string) error {
 	data, err := ioutil.ReadFile(file)
 	if err != nil {
 		return err
 	}
 
 	d := make(map[string]interface{})
-	return json.Unmarshal(data, &d)
+	return &LoadError{
+		Path:    path,
+		Format:  jsonKey,
+		Line:    1,
+		Column:  1,
+		Snippet: strings.SplitN(string(data), "\n", 2)[0],
+		Err:     json.Unmarshal(data, &d),
+		caret:   1,
+	}
+}
+
+func mustAbs(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		panic(err)
+	}
+	return abs
 }
 
 func TestLoadFile(t *testing.T) {
@@ -68,6 +91,14 @@ func TestLoadFile(t *testing.T) {
 				},
 			},
 		},
+		{
+			tc:       "should load XML file",
+			filename: "testdata/config.xml",
+			expect: ConfigMap{
+				"foo":   "Bar",
+				"level": "5.0",
+			},
+		},
 		{
 			tc:       "should error on non-existent file",
 			filename: "testdata/missing.config.json",
@@ -80,14 +111,14 @@ func TestLoadFile(t *testing.T) {
 		},
 		{
 			tc:       "should error on unknown file extension",
-			filename: "testdata/config.xml",
-			err:      ErrNoFileLoader{ext: "xml"},
+			filename: "testdata/config.ini",
+			err:      ErrNoFileLoader{ext: "ini"},
 			expect:   nil,
 		},
 		{
 			tc:       "should error on invalid file contents",
 			filename: "testdata/invalid/config.bad.json",
-			err:      getJSONFileErr("testdata/invalid/config.bad.json"),
+			err:      getJSONFileErr("testdata/invalid/config.bad.json", "testdata/invalid/config.bad.json"),
 			expect:   nil,
 		},
 	}
@@ -139,7 +170,7 @@ func TestLoadDirectory(t *testing.T) {
 			tc:      "should error if directory contains invalid files",
 			dir:     "testdata/invalid",
 			recurse: false,
-			err:     getJSONFileErr("testdata/invalid/config.bad.json"),
+			err:     getJSONFileErr("testdata/invalid/config.bad.json", mustAbs("testdata/invalid/config.bad.json")),
 		},
 	}
 
@@ -154,3 +185,173 @@ func TestLoadDirectory(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONDecoder(t *testing.T) {
+	testIO := []struct {
+		tc      string
+		decoder *JSONDecoder
+		input   string
+		expect  map[string]interface{}
+	}{
+		{
+			tc:      "should decode numbers as float64 by default",
+			decoder: &JSONDecoder{},
+			input:   `{"id": 9007199254740993, "ratio": 0.5}`,
+			expect: map[string]interface{}{
+				"id":    float64(9007199254740993),
+				"ratio": 0.5,
+			},
+		},
+		{
+			tc:      "should decode numbers as json.Number",
+			decoder: &JSONDecoder{Numbers: JSONNumbersAsNumber},
+			input:   `{"id": 9007199254740993, "ratio": 0.5}`,
+			expect: map[string]interface{}{
+				"id":    json.Number("9007199254740993"),
+				"ratio": json.Number("0.5"),
+			},
+		},
+		{
+			tc:      "should decode integral numbers as int64",
+			decoder: &JSONDecoder{Numbers: JSONNumbersAsInt64},
+			input:   `{"id": 9007199254740993, "ratio": 0.5, "big": 18446744073709551615, "nested": {"ids": [1, 2.0, 1e3]}}`,
+			expect: map[string]interface{}{
+				"id":    int64(9007199254740993),
+				"ratio": 0.5,
+				"big":   json.Number("18446744073709551615"),
+				"nested": map[string]interface{}{
+					"ids": []interface{}{int64(1), 2.0, 1000.0},
+				},
+			},
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			actual, err := test.decoder.Load(strings.NewReader(test.input))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
+
+func TestLoadReader(t *testing.T) {
+	testIO := []struct {
+		tc     string
+		input  string
+		format string
+		level  ConfigLevel
+		err    error
+		expect ConfigMap
+	}{
+		{
+			tc:     "should load JSON into the specified level",
+			input:  `{"foo": "bar"}`,
+			format: "json",
+			level:  OverrideLevel,
+			expect: ConfigMap{"foo": "bar"},
+		},
+		{
+			tc:     "should accept a format with a leading dot",
+			input:  `<Config><foo>bar</foo></Config>`,
+			format: ".xml",
+			level:  DefaultLevel,
+			expect: ConfigMap{"foo": "bar"},
+		},
+		{
+			tc:     "should error on unknown format",
+			input:  `foo = bar`,
+			format: "ini",
+			level:  FileLevel,
+			err:    ErrNoFileLoader{ext: "ini"},
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			v := New()
+			err := v.LoadReader(strings.NewReader(test.input), test.format, test.level)
+
+			assertEqualErrors(t, test.err, err)
+			st := v.Store.(*DefaultConfigStore)
+			assert.EqualValues(t, test.expect, st.config[test.level])
+		})
+	}
+}
+
+func TestLoadBytes(t *testing.T) {
+	v := New()
+	assert.NoError(t, v.LoadBytes([]byte(`{"foo": "bar"}`), "json", DefaultLevel))
+	assert.Equal(t, "bar", v.Get("foo"))
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"defaults/config.json":     {Data: []byte(`{"foo": "bar", "level": 5.0}`)},
+		"defaults/log.xml":         {Data: []byte(`<Config><log><level>info</level></log></Config>`)},
+		"defaults/README.md":       {Data: []byte(`# not a config`)},
+		"defaults/sub/config.json": {Data: []byte(`{"foo": "baz"}`)},
+	}
+
+	t.Run("should load a single file", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadFS(fsys, "defaults/config.json"))
+		st := v.Store.(*DefaultConfigStore)
+		assert.EqualValues(t, ConfigMap{"foo": "bar", "level": 5.0}, st.config[FileLevel])
+	})
+
+	t.Run("should error on missing file", func(t *testing.T) {
+		v := New()
+		assert.True(t, errors.Is(v.LoadFS(fsys, "missing.json"), fs.ErrNotExist))
+	})
+
+	t.Run("should load a single directory", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadDirectoryFS(fsys, "defaults", false))
+		st := v.Store.(*DefaultConfigStore)
+		assert.EqualValues(t, ConfigMap{
+			"foo":   "bar",
+			"level": 5.0,
+			"log":   ConfigMap{"level": "info"},
+		}, st.config[FileLevel])
+	})
+
+	t.Run("should recursively load directories", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadDirectoryFS(fsys, "defaults", true))
+		assert.Equal(t, "baz", v.Get("foo"))
+	})
+
+	t.Run("should load from an os directory", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadDirectoryFS(os.DirFS("testdata/sub"), ".", true))
+		assert.Equal(t, "another", v.Get("and"))
+	})
+}
+
+func TestLoadFileAt(t *testing.T) {
+	v := New()
+	assert.NoError(t, v.LoadFileAt(DefaultLevel, "testdata/config.json"))
+	assert.NoError(t, v.LoadFileAt(OverrideLevel, "testdata/sub/sub-sub/more.config.json"))
+
+	st := v.Store.(*DefaultConfigStore)
+	assert.EqualValues(t, ConfigMap{"foo": "bar", "level": 5.0}, st.config[DefaultLevel])
+	assert.Nil(t, st.config[FileLevel])
+	assert.Equal(t, "baz", v.Get("foo"))
+}
+
+func TestLoadDirectoryAt(t *testing.T) {
+	v := New()
+	assert.NoError(t, v.LoadDirectoryAt(DefaultLevel, "testdata/layered", false))
+
+	st := v.Store.(*DefaultConfigStore)
+	assert.EqualValues(t, ConfigMap{
+		"foo":   "local",
+		"level": 10.0,
+		"owner": "ops",
+		"log": ConfigMap{
+			"level": "debug",
+			"file":  "/var/log/app.log",
+		},
+	}, st.config[DefaultLevel])
+}
@@ -24,6 +24,10 @@ type FlagsetResolver struct {
 	Flags     *flag.FlagSet
 	Arguments []string
 
+	// Mapper, if set, maps the segments of each key to the name of the flag to
+	// lookup, in place of joining them with FlagSeparator
+	Mapper KeyMapper
+
 	// A map containing only the names and values of flags that were actually
 	// specified on the command line, as determined by a call to flag.Visit or
 	// flag.FlagSet.Visit.
@@ -96,7 +100,11 @@ func (r *FlagsetResolver) Resolve(keys []string, _ ConfigMap) (val interface{},
 	// Leverage our cached map of flags and their values (generated as a part
 	// of the call to r.parse() above) rather than iterating over all provided
 	// flags every time Resolve is called.
-	if value, ok := r.cachedValueMap[strings.Join(keys, FlagSeparator)]; ok {
+	name := strings.Join(keys, FlagSeparator)
+	if r.Mapper != nil {
+		name = r.Mapper(keys)
+	}
+	if value, ok := r.cachedValueMap[name]; ok {
 		return value, ok
 	}
 	return nil, false
@@ -33,6 +33,11 @@ func (e *CoerceErr) Error() string {
 	return fmt.Sprintf("venom: can not coerce %T to %q", e.From, e.To)
 }
 
+// Unwrap returns the underlying error which caused the coercion to fail, if any
+func (e *CoerceErr) Unwrap() error {
+	return e.Err
+}
+
 `
 
 const packageIntro = packageHdr + pkgImports + coerceErr
@@ -80,7 +85,41 @@ func kindTitle(k reflect.Kind) string {
 	)
 }
 
+// numberCoercers maps numeric kinds to the helper used to convert alternate
+// number representations, such as json.Number, into that kind
+var numberCoercers = map[reflect.Kind]string{
+	reflect.Int:     "signedNumber(val, 0)",
+	reflect.Int8:    "signedNumber(val, 8)",
+	reflect.Int16:   "signedNumber(val, 16)",
+	reflect.Int32:   "signedNumber(val, 32)",
+	reflect.Int64:   "signedNumber(val, 64)",
+	reflect.Uint:    "unsignedNumber(val, 0)",
+	reflect.Uint8:   "unsignedNumber(val, 8)",
+	reflect.Uint16:  "unsignedNumber(val, 16)",
+	reflect.Uint32:  "unsignedNumber(val, 32)",
+	reflect.Uint64:  "unsignedNumber(val, 64)",
+	reflect.Float32: "floatNumber(val, 32)",
+	reflect.Float64: "floatNumber(val, 64)",
+}
+
+func writeNumberCaster(to reflect.Kind) string {
+	return fmt.Sprintf(`if value, ok := val.(%s); ok {
+		return value, nil
+	}
+
+	value, ok, err := %s
+	if !ok || err != nil {
+		return %v, &CoerceErr{From: val, To: %q, Err: err}
+	}
+	return %s(value), nil
+`, to, numberCoercers[to], zeroValue(to), to, to)
+}
+
 func writeCaster(to reflect.Kind) string {
+	if _, ok := numberCoercers[to]; ok {
+		return writeNumberCaster(to)
+	}
+
 	zeroValFmt := "%v"
 	if to == reflect.String {
 		zeroValFmt = "%q"
@@ -1,5 +1,13 @@
 package venom
 
+import (
+	"context"
+	"crypto/ed25519"
+	"io"
+	"io/fs"
+	"time"
+)
+
 var v *Venom
 
 func init() {
@@ -35,6 +43,12 @@ func SetOverride(key string, value interface{}) {
 	v.SetOverride(key, value)
 }
 
+// Unset removes the provided key from the specified level of the global venom
+// instance
+func Unset(level ConfigLevel, key string) {
+	v.Unset(level, key)
+}
+
 // Get retrieves the requested key from the global venom instance
 func Get(key string) interface{} {
 	return v.Get(key)
@@ -46,6 +60,24 @@ func Find(key string) (interface{}, bool) {
 	return v.Find(key)
 }
 
+// BindEnv binds the provided key to the provided environment variable names
+// in the global venom instance
+func BindEnv(key string, names ...string) {
+	v.BindEnv(key, names...)
+}
+
+// Bindings returns the environment variable bindings added via BindEnv to the
+// global venom instance
+func Bindings() *EnvBindings {
+	return v.Bindings()
+}
+
+// Lookup searches for the given key in the same manner as Find, additionally
+// returning any error reported by an ErrorResolver
+func Lookup(key string) (interface{}, bool, error) {
+	return v.Lookup(key)
+}
+
 // LoadFile loads the file from the provided path into Venoms configs. If the
 // file can't be opened, if no loader for the files extension exists, or if
 // loading the file fails, an error is returned
@@ -59,6 +91,184 @@ func LoadDirectory(dir string, recurse bool) error {
 	return v.LoadDirectory(dir, recurse)
 }
 
+// LoadFileAt loads the file from the provided path into the specified
+// ConfigLevel of the global venom instance
+func LoadFileAt(level ConfigLevel, name string) error {
+	return v.LoadFileAt(level, name)
+}
+
+// LoadDirectoryAt loads any config files found in the provided directory into
+// the specified ConfigLevel of the global venom instance, optionally recursing
+// into any sub-directories
+func LoadDirectoryAt(level ConfigLevel, dir string, recurse bool) error {
+	return v.LoadDirectoryAt(level, dir, recurse)
+}
+
+// LoadDirectoryWith loads any config files found in the provided directory
+// into the specified ConfigLevel of the global venom instance, as configured
+// by the provided DirectoryOptions
+func LoadDirectoryWith(level ConfigLevel, dir string, opts DirectoryOptions) error {
+	return v.LoadDirectoryWith(level, dir, opts)
+}
+
+// LoadEnvironment loads every environment variable with the provided prefix
+// into the specified ConfigLevel of the global venom instance
+func LoadEnvironment(prefix string, level ConfigLevel) error {
+	return v.LoadEnvironment(prefix, level)
+}
+
+// LoadEnvironmentFrom loads every environment variable provided by src with
+// the provided prefix into the specified ConfigLevel of the global venom
+// instance
+func LoadEnvironmentFrom(src EnvironmentSource, prefix string, level ConfigLevel) error {
+	return v.LoadEnvironmentFrom(src, prefix, level)
+}
+
+// LoadKeyPerFileDirectory loads a directory containing one file per config
+// key into the specified ConfigLevel of the global venom instance
+func LoadKeyPerFileDirectory(dir string, level ConfigLevel) error {
+	return v.LoadKeyPerFileDirectory(dir, level)
+}
+
+// LoadReader loads config data from the provided io.Reader into the specified
+// ConfigLevel of the global venom instance, using the IOFileLoader registered
+// for the provided format
+func LoadReader(r io.Reader, format string, level ConfigLevel) error {
+	return v.LoadReader(r, format, level)
+}
+
+// LoadBytes loads the provided config data into the specified ConfigLevel of
+// the global venom instance, using the IOFileLoader registered for the
+// provided format
+func LoadBytes(b []byte, format string, level ConfigLevel) error {
+	return v.LoadBytes(b, format, level)
+}
+
+// LoadFS loads the file at the provided path within fsys into the global
+// venom instance
+func LoadFS(fsys fs.FS, name string) error {
+	return v.LoadFS(fsys, name)
+}
+
+// LoadDirectoryFS loads any config files found in the provided directory of
+// fsys into the global venom instance, optionally recursing into any
+// sub-directories
+func LoadDirectoryFS(fsys fs.FS, dir string, recurse bool) error {
+	return v.LoadDirectoryFS(fsys, dir, recurse)
+}
+
+// SetConfigName sets the name, without an extension, of the config file to
+// search for when calling ReadInConfig on the global venom instance
+func SetConfigName(name string) {
+	v.SetConfigName(name)
+}
+
+// AddConfigPath appends the provided directories to the list of paths which
+// are searched by the global venom instance when calling ReadInConfig
+func AddConfigPath(paths ...string) {
+	v.AddConfigPath(paths...)
+}
+
+// ReadInConfig loads the first config file found within the config search
+// paths of the global venom instance
+func ReadInConfig() error {
+	return v.ReadInConfig()
+}
+
+// ReadInAllConfigs loads every config file found within the config search
+// paths of the global venom instance
+func ReadInAllConfigs() error {
+	return v.ReadInAllConfigs()
+}
+
+// ConfigFilesUsed returns the paths of the config files loaded by the most
+// recent call to ReadInConfig or ReadInAllConfigs on the global venom instance
+func ConfigFilesUsed() []string {
+	return v.ConfigFilesUsed()
+}
+
+// SetProfiles explicitly sets the active profiles of the global venom instance
+func SetProfiles(profiles ...string) {
+	v.SetProfiles(profiles...)
+}
+
+// SetProfileEnv sets the name of an environment variable containing a comma
+// separated list of the active profiles of the global venom instance
+func SetProfileEnv(name string) {
+	v.SetProfileEnv(name)
+}
+
+// SetProfileKey sets the config key whose value contains the active profiles
+// of the global venom instance
+func SetProfileKey(key string) {
+	v.SetProfileKey(key)
+}
+
+// Reload re-reads every file and directory loaded into the global venom
+// instance, rebuilding the ConfigLevels they were loaded into
+func Reload() error {
+	return v.Reload()
+}
+
+// RegisterValidator registers a Validator which is run against the reloaded
+// configs of the global venom instance before they are applied
+func RegisterValidator(fn Validator) {
+	v.RegisterValidator(fn)
+}
+
+// AddTrustedKeys adds the provided ed25519 public keys to the set of keys
+// trusted to sign the config files of the global venom instance
+func AddTrustedKeys(keys ...ed25519.PublicKey) {
+	v.AddTrustedKeys(keys...)
+}
+
+// SetStrictSignatures toggles whether the global venom instance rejects config
+// files which are not signed by a trusted key
+func SetStrictSignatures(strict bool) {
+	v.SetStrictSignatures(strict)
+}
+
+// SetLimits restricts the size and shape of the config data loaded into the
+// global venom instance
+func SetLimits(limits LoadLimits) {
+	v.SetLimits(limits)
+}
+
+// Limits returns the LoadLimits of the global venom instance
+func Limits() LoadLimits {
+	return v.Limits()
+}
+
+// WatchFiles polls every file and directory loaded into the global venom
+// instance at the provided interval, reloading them when they change
+func WatchFiles(ctx context.Context, interval time.Duration) <-chan error {
+	return v.WatchFiles(ctx, interval)
+}
+
+// WriteFile writes the effective configs of the global venom instance to the
+// file at the provided path
+func WriteFile(name string) error {
+	return v.WriteFile(name)
+}
+
+// WriteFileWith writes the effective configs of the global venom instance to
+// the file at the provided path, using the provided WriteOptions
+func WriteFileWith(name string, opts WriteOptions) error {
+	return v.WriteFileWith(name, opts)
+}
+
+// WriteLevel writes the config data stored in the provided ConfigLevel of the
+// global venom instance to the file at the provided path
+func WriteLevel(level ConfigLevel, name string) error {
+	return v.WriteLevel(level, name)
+}
+
+// Explain returns an Explanation of where the value of the given key was
+// resolved from in the global venom instance
+func Explain(key string) Explanation {
+	return v.Explain(key)
+}
+
 // Clear removes all data from the ConfigMap and resets the heap of config
 // levels
 func Clear() {
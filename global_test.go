@@ -185,7 +185,7 @@ func TestGlobalAlias(t *testing.T) {
 }
 
 func TestGlobalRegisterResolver(t *testing.T) {
-	v.RegisterResolver(EnvironmentLevel, defaultEnvResolver)
+	v.RegisterResolver(EnvironmentLevel, v.defaultEnvResolver())
 	st := v.Store.(*DefaultConfigStore)
 	assert.Contains(t, st.resolvers, EnvironmentLevel)
 }
@@ -2,4 +2,10 @@ module github.com/moogar0880/venom
 
 require github.com/stretchr/testify v1.8.4
 
-go 1.11
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+go 1.17
@@ -20,7 +20,8 @@ func (h ConfigLevelHeap) Less(i, j int) bool {
 }
 func (h ConfigLevelHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
 
-// Push pushes a new value onto the heap, inserting in sort order
+// Push appends a new value to the heap. As with any heap.Interface, values
+// should be pushed via heap.Push, which restores the heap ordering afterwards.
 func (h *ConfigLevelHeap) Push(x interface{}) {
 	// Push and Pop use pointer receivers because they modify the slice's length,
 	// not just its contents.
@@ -54,3 +54,22 @@ func TestConfigHeap(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigLevelHeapInterface(t *testing.T) {
+	h := NewConfigLevelHeap()
+	for _, level := range []ConfigLevel{FileLevel, DefaultLevel, EnvironmentLevel} {
+		heap.Push(h, level)
+	}
+	assert.Equal(t, EnvironmentLevel, heap.Pop(h))
+
+	// values pushed after a Pop are still ordered by precedence
+	for _, level := range []ConfigLevel{OverrideLevel, FlagLevel, EnvironmentLevel} {
+		heap.Push(h, level)
+	}
+
+	var popped []ConfigLevel
+	for h.Len() > 0 {
+		popped = append(popped, heap.Pop(h).(ConfigLevel))
+	}
+	assert.Equal(t, []ConfigLevel{OverrideLevel, FlagLevel, EnvironmentLevel, FileLevel, DefaultLevel}, popped)
+}
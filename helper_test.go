@@ -14,6 +14,13 @@ type TestLogger struct{}
 func (tl *TestLogger) LogWrite(level ConfigLevel, key string, val interface{}) {}
 func (tl *TestLogger) LogRead(key string, val interface{}, bl bool)            {}
 
+// minimalStore is a ConfigStore which only implements the methods required by
+// the ConfigStore interface, hiding any optional interfaces implemented by the
+// ConfigStore it wraps
+type minimalStore struct {
+	ConfigStore
+}
+
 // kv is a test struct containing a (k)ey and a (v)alue
 type kv struct {
 	k string
@@ -0,0 +1,171 @@
+package venom
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Directives are recognized as top level keys of the config data returned by
+// any IOFileLoader. They may be changed for formats, such as XML, which can not
+// represent keys beginning with a "$".
+var (
+	// IncludeDirective is the top level key of a config file listing other
+	// config files to be included beneath it. Its value may be either a
+	// single path or a list of paths, which may contain glob patterns.
+	IncludeDirective = "$include"
+
+	// ExtendsDirective is the top level key of a config file naming the
+	// config file, or files, that it extends. Extended files are applied
+	// beneath both the extending file and any of its included files.
+	ExtendsDirective = "$extends"
+)
+
+// ErrIncludeCycle is the error wrapped by an IncludeError when a config file
+// directly or indirectly includes itself
+var ErrIncludeCycle = errors.New("include cycle")
+
+// An IncludeError is returned when an include or extends directive within a
+// config file can not be resolved
+type IncludeError struct {
+	// File is the path of the file containing the directive
+	File string
+
+	// Include is the path that could not be included
+	Include string
+
+	// Chain lists the files which led to the failing include, starting with
+	// the file originally being loaded
+	Chain []string
+
+	Err error
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current IncludeError instance
+func (e *IncludeError) Error() string {
+	if errors.Is(e.Err, ErrIncludeCycle) {
+		return fmt.Sprintf("venom: %s: include cycle: %s -> %s", e.File, strings.Join(e.Chain, " -> "), e.Include)
+	}
+	return fmt.Sprintf("venom: %s: can not include %q: %s", e.File, e.Include, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *IncludeError) Unwrap() error {
+	return e.Err
+}
+
+// A loadedFile is the config data read from a single file
+type loadedFile struct {
+	name string
+	data map[string]interface{}
+}
+
+// readFileWithIncludes reads the named config file along with any files it
+// includes or extends, returning them in increasing order of precedence. The
+// named file is always the last of the returned files.
+func (v *Venom) readFileWithIncludes(name string) ([]loadedFile, error) {
+	return v.resolveIncludes(name, nil)
+}
+
+func (v *Venom) resolveIncludes(name string, chain []string) ([]loadedFile, error) {
+	data, err := v.readFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	chain = append(chain[:len(chain):len(chain)], abs)
+
+	includes, err := directivePaths(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []loadedFile
+	for _, include := range includes {
+		includeAbs, err := filepath.Abs(include)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parent := range chain {
+			if parent == includeAbs {
+				return nil, &IncludeError{File: name, Include: include, Chain: chain, Err: ErrIncludeCycle}
+			}
+		}
+
+		included, err := v.resolveIncludes(include, chain)
+		if err != nil {
+			var includeErr *IncludeError
+			if errors.As(err, &includeErr) {
+				return nil, err
+			}
+			return nil, &IncludeError{File: name, Include: include, Chain: chain, Err: err}
+		}
+		files = append(files, included...)
+	}
+
+	return append(files, loadedFile{name: name, data: data}), nil
+}
+
+// directivePaths removes any include and extends directives from the provided
+// config data, returning the paths they reference resolved relative to the
+// directory of the named file. Extended files are returned before included
+// files.
+func directivePaths(name string, data map[string]interface{}) ([]string, error) {
+	var paths []string
+	for _, directive := range []string{ExtendsDirective, IncludeDirective} {
+		val, ok := data[directive]
+		if !ok {
+			continue
+		}
+		delete(data, directive)
+
+		var patterns []string
+		switch actual := val.(type) {
+		case string:
+			patterns = []string{actual}
+		case []interface{}:
+			for _, item := range actual {
+				pattern, ok := item.(string)
+				if !ok {
+					return nil, &IncludeError{File: name, Include: fmt.Sprint(item), Err: fmt.Errorf("%s must contain only strings", directive)}
+				}
+				patterns = append(patterns, pattern)
+			}
+		default:
+			return nil, &IncludeError{File: name, Include: fmt.Sprint(val), Err: fmt.Errorf("%s must be a string or a list of strings", directive)}
+		}
+
+		for _, pattern := range patterns {
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(filepath.Dir(name), pattern)
+			}
+
+			if !strings.ContainsAny(pattern, "*?[") {
+				paths = append(paths, pattern)
+				continue
+			}
+
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, &IncludeError{File: name, Include: pattern, Err: err}
+			}
+			sort.Strings(matches)
+			for _, match := range matches {
+				// globs such as "*.json" may match the including file, which
+				// is skipped rather than being reported as a cycle
+				if filepath.Clean(match) != filepath.Clean(name) {
+					paths = append(paths, match)
+				}
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,119 @@
+package venom
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFileIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app.json": `{
+			"$extends": "base.json",
+			"$include": ["common.json", "db/*.json"],
+			"name": "app"
+		}`,
+		"base.json":    `{"name": "base", "timeout": 10, "log": {"level": "info"}}`,
+		"common.json":  `{"timeout": 30, "log": {"file": "/var/log/app.log"}}`,
+		"db/a.json":    `{"db": {"host": "a.db", "port": 5432}}`,
+		"db/b.json":    `{"db": {"host": "b.db"}}`,
+		"db/notes.txt": `not a config`,
+	})
+
+	v := New()
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "app.json")))
+
+	assert.Equal(t, "app", v.Get("name"))
+	assert.Equal(t, 30.0, v.Get("timeout"))
+	assert.Equal(t, "info", v.Get("log.level"))
+	assert.Equal(t, "/var/log/app.log", v.Get("log.file"))
+	assert.Equal(t, "b.db", v.Get("db.host"))
+	assert.Equal(t, 5432.0, v.Get("db.port"))
+	assert.Nil(t, v.Get(IncludeDirective))
+	assert.Nil(t, v.Get(ExtendsDirective))
+
+	assert.Equal(t, filepath.Join(dir, "app.json"), v.Explain("name").File)
+	assert.Equal(t, filepath.Join(dir, "common.json"), v.Explain("timeout").File)
+	assert.Equal(t, filepath.Join(dir, "base.json"), v.Explain("log.level").File)
+	assert.Equal(t, filepath.Join(dir, "db", "a.json"), v.Explain("db.port").File)
+}
+
+func TestLoadFileIncludesWithCustomDirective(t *testing.T) {
+	defer func(directive string) { IncludeDirective = directive }(IncludeDirective)
+	IncludeDirective = "include"
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app.xml":     `<Config><include>common.json</include><name>app</name></Config>`,
+		"common.json": `{"timeout": 30}`,
+	})
+
+	v := New()
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "app.xml")))
+	assert.Equal(t, 30.0, v.Get("timeout"))
+	assert.Equal(t, "app", v.Get("name"))
+}
+
+func TestLoadFileIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"cycle-a.json":    `{"$include": "cycle-b.json"}`,
+		"cycle-b.json":    `{"$include": ["./cycle-a.json"]}`,
+		"self.json":       `{"$extends": "self.json"}`,
+		"glob/glob.json":  `{"$include": "*.json", "foo": "bar"}`,
+		"glob/other.json": `{"$include": "glob.json"}`,
+		"missing.json":    `{"$include": "nested.json"}`,
+		"nested.json":     `{"$include": "does-not-exist.json"}`,
+		"invalid.json":    `{"$include": 12}`,
+		"bad-entry.json":  `{"$include": ["a.json", 12]}`,
+	})
+
+	t.Run("should detect include cycles", func(t *testing.T) {
+		v := New()
+		err := v.LoadFile(filepath.Join(dir, "cycle-a.json"))
+
+		var includeErr *IncludeError
+		if assert.True(t, errors.As(err, &includeErr), "%v", err) {
+			assert.True(t, errors.Is(err, ErrIncludeCycle))
+			assert.Equal(t, filepath.Join(dir, "cycle-b.json"), includeErr.File)
+			assert.Len(t, includeErr.Chain, 2)
+			assert.Contains(t, err.Error(), "include cycle")
+		}
+		assert.Equal(t, 0, v.Size())
+	})
+
+	t.Run("should detect files including themselves", func(t *testing.T) {
+		err := New().LoadFile(filepath.Join(dir, "self.json"))
+		assert.True(t, errors.Is(err, ErrIncludeCycle), "%v", err)
+	})
+
+	t.Run("should skip the including file when matched by a glob", func(t *testing.T) {
+		err := New().LoadFile(filepath.Join(dir, "glob", "glob.json"))
+		assert.True(t, errors.Is(err, ErrIncludeCycle), "%v", err)
+	})
+
+	t.Run("should report missing files", func(t *testing.T) {
+		v := New()
+		err := v.LoadFile(filepath.Join(dir, "missing.json"))
+
+		var includeErr *IncludeError
+		if assert.True(t, errors.As(err, &includeErr), "%v", err) {
+			assert.Equal(t, filepath.Join(dir, "nested.json"), includeErr.File)
+			assert.Equal(t, filepath.Join(dir, "does-not-exist.json"), includeErr.Include)
+			assert.True(t, errors.Is(err, os.ErrNotExist))
+		}
+		assert.Equal(t, 0, v.Size())
+	})
+
+	t.Run("should reject invalid directive values", func(t *testing.T) {
+		for _, name := range []string{"invalid.json", "bad-entry.json"} {
+			var includeErr *IncludeError
+			err := New().LoadFile(filepath.Join(dir, name))
+			assert.True(t, errors.As(err, &includeErr), "%v", err)
+		}
+	})
+}
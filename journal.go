@@ -0,0 +1,469 @@
+package venom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultJournalCompactAfter is the number of records appended to a journal
+// before it is compacted, when JournalOptions does not specify CompactAfter.
+const DefaultJournalCompactAfter = 1000
+
+const (
+	journalSet   = "set"
+	journalUnset = "unset"
+)
+
+// A journalRecord is a single line of a journal, recording a write to a
+// journaled ConfigLevel
+type journalRecord struct {
+	Op    string      `json:"op"`
+	Level ConfigLevel `json:"level"`
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// A JournalError is returned when a journal can not be replayed because one of
+// its records is corrupt
+type JournalError struct {
+	Path string
+	Line int
+	Err  error
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current JournalError instance
+func (e *JournalError) Error() string {
+	return fmt.Sprintf("venom: %s: line %d: %s", e.Path, e.Line, e.Err)
+}
+
+// Unwrap returns the reason the record could not be replayed
+func (e *JournalError) Unwrap() error {
+	return e.Err
+}
+
+// JournalOptions configures which ConfigLevels a JournalStore persists, and how
+// often its journal is compacted
+type JournalOptions struct {
+	// Levels lists the ConfigLevels whose writes are journaled. If empty, only
+	// the OverrideLevel is journaled.
+	Levels []ConfigLevel
+
+	// CompactAfter is the number of records appended to the journal before it
+	// is compacted. If zero, DefaultJournalCompactAfter is used.
+	CompactAfter int
+}
+
+// A JournalStore wraps a ConfigStore, persisting every SetLevel and Unset of
+// the journaled ConfigLevels to a journal file so that runtime changes, such as
+// those made via SetOverride, survive restarts.
+//
+// Every write is appended to the journal as a single line and synced to disk
+// before it is applied to the wrapped store. When the store is created, the
+// journal is replayed into the wrapped store, ignoring a partially written
+// final record left behind by a crash. The journal is then compacted, by
+// atomically replacing it with the minimal set of records that reproduce the
+// current values, which also happens every CompactAfter writes.
+//
+// Only writes made via SetLevel and Unset are journaled, meaning that data
+// loaded from files, or merged via Merge or ReplaceLevel, is never persisted.
+//
+// Values are journaled as JSON, so only the JSON form of a value survives a
+// restart: integers, time.Duration values and integral floats are replayed as
+// int64, other floats as float64, slices as []interface{}, and maps and structs
+// as map[string]interface{} values keyed by their JSON field names. Values
+// which can not be encoded as JSON, such as channels, are not applied, and are
+// reported by Err.
+type JournalStore struct {
+	store ConfigStore
+	path  string
+
+	mu      sync.Mutex
+	levels  map[ConfigLevel]bool
+	state   map[ConfigLevel]ConfigMap
+	file    *os.File
+	records int
+	compact int
+	err     error
+}
+
+// NewJournalStore returns a newly allocated JournalStore which wraps the
+// provided ConfigStore and journals writes to the OverrideLevel to the file at
+// the provided path. The file is created if it does not exist, and replayed
+// into the ConfigStore if it does.
+func NewJournalStore(s ConfigStore, path string) (*JournalStore, error) {
+	return NewJournalStoreWith(s, path, JournalOptions{})
+}
+
+// NewJournalStoreWith returns a newly allocated JournalStore which wraps the
+// provided ConfigStore, configured by the provided JournalOptions. See
+// NewJournalStore for more details.
+func NewJournalStoreWith(s ConfigStore, path string, opts JournalOptions) (*JournalStore, error) {
+	levels := opts.Levels
+	if len(levels) == 0 {
+		levels = []ConfigLevel{OverrideLevel}
+	}
+
+	j := &JournalStore{
+		store:   s,
+		path:    path,
+		levels:  make(map[ConfigLevel]bool, len(levels)),
+		state:   make(map[ConfigLevel]ConfigMap),
+		compact: opts.CompactAfter,
+	}
+	if j.compact <= 0 {
+		j.compact = DefaultJournalCompactAfter
+	}
+	for _, level := range levels {
+		j.levels[level] = true
+	}
+
+	if err := j.replay(); err != nil {
+		return nil, err
+	}
+	if err := j.Compact(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// replay applies every record of the journal to the wrapped store
+func (j *JournalStore) replay() error {
+	data, err := ioutil.ReadFile(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	for index, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		record, err := decodeJournalRecord(line)
+		if err != nil {
+			if index == len(lines)-1 {
+				// the final record was only partially written before a crash,
+				// and was never applied
+				return nil
+			}
+			return &JournalError{Path: j.path, Line: index + 1, Err: err}
+		}
+
+		if !j.levels[record.Level] {
+			continue
+		}
+		if err := j.check(record); err != nil {
+			return &JournalError{Path: j.path, Line: index + 1, Err: err}
+		}
+		j.apply(record)
+	}
+	return nil
+}
+
+// decodeJournalRecord decodes a single line of a journal
+func decodeJournalRecord(line []byte) (journalRecord, error) {
+	var record journalRecord
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&record); err != nil {
+		return record, err
+	}
+
+	switch record.Op {
+	case journalSet, journalUnset:
+	default:
+		return record, fmt.Errorf("unknown operation %q", record.Op)
+	}
+	if record.Key == "" {
+		return record, fmt.Errorf("record has no key")
+	}
+	record.Value = convertJSONNumbers(record.Value)
+	return record, nil
+}
+
+// check returns an error if the provided record can not be applied to the
+// journaled state, as it sets a key nested beneath a value which is not a map
+func (j *JournalStore) check(record journalRecord) error {
+	if record.Op != journalSet {
+		return nil
+	}
+
+	config := j.state[record.Level]
+	keys := strings.Split(record.Key, Delim)
+	for index, key := range keys[:len(keys)-1] {
+		val, ok := config[key]
+		if !ok {
+			return nil
+		}
+		nested, ok := val.(ConfigMap)
+		if !ok {
+			parent := strings.Join(keys[:index+1], Delim)
+			return fmt.Errorf("can not set %q as %q is not a map", record.Key, parent)
+		}
+		config = nested
+	}
+	return nil
+}
+
+// apply applies the provided record to the journaled state and the wrapped
+// store
+func (j *JournalStore) apply(record journalRecord) {
+	keys := strings.Split(record.Key, Delim)
+	switch record.Op {
+	case journalSet:
+		if _, ok := j.state[record.Level]; !ok {
+			j.state[record.Level] = make(ConfigMap)
+		}
+		setNested(j.state[record.Level], keys, record.Value)
+		j.store.SetLevel(record.Level, record.Key, record.Value)
+	case journalUnset:
+		if state, ok := j.state[record.Level]; ok {
+			unsetNested(state, keys)
+		}
+		unsetKey(j.store, record.Level, record.Key)
+	}
+}
+
+// write appends the provided record to the journal, before applying it. If
+// the record can not be applied, or can not be written, it is neither written
+// nor applied, and the error is reported by Err.
+func (j *JournalStore) write(record journalRecord) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.check(record); err != nil {
+		j.err = fmt.Errorf("venom: %s: %w", j.path, err)
+		return
+	}
+	if err := j.append(record); err != nil {
+		j.err = err
+		return
+	}
+	j.apply(record)
+
+	j.records++
+	if j.records >= j.compact {
+		if err := j.compactLocked(); err != nil {
+			j.err = err
+		}
+	}
+}
+
+// append writes the provided record to the end of the journal and syncs it to
+// disk
+func (j *JournalStore) append(record journalRecord) error {
+	if j.file == nil {
+		return fmt.Errorf("venom: %s: journal is closed", j.path)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+// Compact atomically replaces the journal with the minimal set of records that
+// reproduce the current values of the journaled ConfigLevels.
+func (j *JournalStore) Compact() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.compactLocked()
+}
+
+func (j *JournalStore) compactLocked() error {
+	if err := writeAtomic(j.path, 0600, j.writeSnapshot); err != nil {
+		return err
+	}
+
+	// the open journal file has been replaced, so it must be closed even if
+	// the new journal can not be opened, otherwise subsequent records would
+	// be appended to the replaced file and lost
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	j.file = file
+	j.records = 0
+	return nil
+}
+
+// writeSnapshot writes a set record for every value of the journaled state
+func (j *JournalStore) writeSnapshot(w io.Writer) error {
+	levels := make([]ConfigLevel, 0, len(j.state))
+	for level := range j.state {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(a, b int) bool { return levels[a] < levels[b] })
+
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	for _, level := range levels {
+		flat := flattenConfigMap(j.state[level])
+		keys := sortedKeys(flat)
+		for _, key := range keys {
+			record := journalRecord{Op: journalSet, Level: level, Key: key, Value: flat[key]}
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	return buf.Flush()
+}
+
+// Err returns the most recent error encountered while writing to the journal.
+// Writes which could not be journaled are not applied to the wrapped store.
+func (j *JournalStore) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Close closes the journal file. Writes to journaled ConfigLevels made after
+// the journal is closed are not applied, and are reported by Err.
+func (j *JournalStore) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// RegisterResolver registers a custom config resolver for the specified
+// ConfigLevel.
+//
+// Additionally, if the provided level is not already in the current collection
+// of active config levels, it will be added automatically
+func (j *JournalStore) RegisterResolver(level ConfigLevel, r Resolver) {
+	j.store.RegisterResolver(level, r)
+}
+
+// SetLevel is a generic key/value setter method. It sets the provided k/v at
+// the specified level inside the map, conditionally creating a new ConfigMap if
+// one didn't previously exist.
+//
+// If the level is journaled, the write is appended to the journal before it is
+// applied.
+func (j *JournalStore) SetLevel(level ConfigLevel, key string, value interface{}) {
+	if !j.levels[level] {
+		j.store.SetLevel(level, key, value)
+		return
+	}
+	j.write(journalRecord{Op: journalSet, Level: level, Key: key, Value: value})
+}
+
+// Unset removes the provided key from the specified level.
+//
+// If the level is journaled, the removal is appended to the journal before it
+// is applied.
+func (j *JournalStore) Unset(level ConfigLevel, key string) {
+	if !j.levels[level] {
+		unsetKey(j.store, level, key)
+		return
+	}
+	j.write(journalRecord{Op: journalUnset, Level: level, Key: key})
+}
+
+// Merge merges the provided config map into the ConfigLevel l, allocating
+// space for ConfigLevel l if the level hasn't already been allocated. Merged
+// data is not journaled.
+func (j *JournalStore) Merge(l ConfigLevel, data ConfigMap) {
+	j.store.Merge(l, data)
+}
+
+// ReplaceLevel replaces the entire contents of the ConfigLevel l with the
+// provided config map, allocating space for ConfigLevel l if the level hasn't
+// already been allocated. Replaced data is not journaled.
+func (j *JournalStore) ReplaceLevel(l ConfigLevel, data ConfigMap) {
+	replaceLevel(j.store, l, data)
+}
+
+// replaceLevelDeferred replaces the contents of the ConfigLevel l of the
+// wrapped ConfigStore, deferring any notifications of the change
+func (j *JournalStore) replaceLevelDeferred(l ConfigLevel, data ConfigMap) func() {
+	return replaceLevelDeferred(j.store, l, data)
+}
+
+// Level returns a copy of the config map stored at the ConfigLevel l, or nil
+// if no space has been allocated for ConfigLevel l.
+func (j *JournalStore) Level(l ConfigLevel) ConfigMap {
+	return storeLevel(j.store, l)
+}
+
+// Levels returns every ConfigLevel which space has been allocated for, in
+// increasing order of precedence.
+func (j *JournalStore) Levels() []ConfigLevel {
+	return storeLevels(j.store)
+}
+
+// Copy returns a new ConfigStore containing a copy of the config data, aliases
+// and resolvers of the wrapped ConfigStore. Writes to the copy are not
+// journaled.
+func (j *JournalStore) Copy() ConfigStore {
+	return copyStore(j.store)
+}
+
+// LogReload logs the result of a reload if the wrapped ConfigStore is a
+// ReloadLogger.
+func (j *JournalStore) LogReload(result ReloadResult) {
+	if logger, ok := j.store.(ReloadLogger); ok {
+		logger.LogReload(result)
+	}
+}
+
+// Alias registers an alias for a given key. This allows consumers to access
+// the same config via a different key, increasing the backwards
+// compatibility of an application.
+func (j *JournalStore) Alias(from, to string) {
+	j.store.Alias(from, to)
+}
+
+// Find searches for the given key, returning the discovered value and a
+// boolean indicating whether or not the key was found
+func (j *JournalStore) Find(key string) (interface{}, bool) {
+	return j.store.Find(key)
+}
+
+// Explain searches for the given key in the same manner as Find, returning an
+// Explanation of the ConfigLevel the value was resolved from.
+func (j *JournalStore) Explain(key string) Explanation {
+	return explainKey(j.store, key)
+}
+
+// Clear removes all data from the wrapped ConfigStore. The journal is left
+// untouched, meaning that journaled values are restored on the next restart.
+func (j *JournalStore) Clear() {
+	j.store.Clear()
+}
+
+// Debug returns the current venom ConfigMap as a pretty-printed JSON string
+func (j *JournalStore) Debug() string {
+	return j.store.Debug()
+}
+
+// Size returns the number of config levels stored in the wrapped ConfigStore.
+func (j *JournalStore) Size() int {
+	return j.store.Size()
+}
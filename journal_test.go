@@ -0,0 +1,209 @@
+package venom
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func openJournal(t *testing.T, path string, opts JournalOptions) (*Venom, *JournalStore) {
+	t.Helper()
+	store, err := NewJournalStoreWith(NewDefaultConfigStore(), path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return NewWithStore(store), store
+}
+
+func journalLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestJournalStoreReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.journal")
+
+	v, store := openJournal(t, path, JournalOptions{})
+	v.SetDefault("db.host", "localhost")
+	v.SetOverride("db.host", "example.com")
+	v.SetOverride("db.port", 5432)
+	v.SetOverride("db.ssl", false)
+	v.SetOverride("log.level", "debug")
+	v.Unset(OverrideLevel, "log.level")
+	assert.NoError(t, store.Err())
+	assert.NoError(t, store.Close())
+
+	v, store = openJournal(t, path, JournalOptions{})
+	assert.Equal(t, "example.com", v.Get("db.host"))
+	assert.Equal(t, int64(5432), v.Get("db.port"))
+	assert.Equal(t, false, v.Get("db.ssl"))
+	assert.Nil(t, v.Get("log.level"))
+	assert.Nil(t, v.Level(DefaultLevel), "non-journaled levels are not persisted")
+
+	// unsetting a journaled key falls back to lower levels once replayed
+	v.SetDefault("db.host", "localhost")
+	v.Unset(OverrideLevel, "db.host")
+	assert.NoError(t, store.Close())
+
+	v, _ = openJournal(t, path, JournalOptions{})
+	v.SetDefault("db.host", "localhost")
+	assert.Equal(t, "localhost", v.Get("db.host"))
+}
+
+func TestJournalStoreReplayTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.journal")
+	type server struct {
+		Host string `json:"host"`
+	}
+
+	v, store := openJournal(t, path, JournalOptions{})
+	v.SetOverride("int", 8080)
+	v.SetOverride("duration", 5*time.Second)
+	v.SetOverride("float", 2.0)
+	v.SetOverride("slice", []string{"a", "b"})
+	v.SetOverride("struct", server{Host: "localhost"})
+	assert.NoError(t, store.Err())
+
+	v.SetOverride("chan", make(chan int))
+	assert.Error(t, store.Err())
+	assert.Nil(t, v.Get("chan"), "values which can not be journaled are not applied")
+	assert.NoError(t, store.Close())
+
+	v, _ = openJournal(t, path, JournalOptions{})
+	assert.Equal(t, int64(8080), v.Get("int"))
+	assert.Equal(t, int64(5*time.Second), v.Get("duration"))
+	assert.Equal(t, int64(2), v.Get("float"))
+	assert.Equal(t, []interface{}{"a", "b"}, v.Get("slice"))
+	assert.Equal(t, map[string]interface{}{"host": "localhost"}, v.Get("struct"))
+}
+
+func TestJournalStoreLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.journal")
+	opts := JournalOptions{Levels: []ConfigLevel{FileLevel, OverrideLevel}}
+
+	v, store := openJournal(t, path, opts)
+	v.SetLevel(FileLevel, "foo", "file")
+	v.SetOverride("bar", "override")
+	v.SetLevel(EnvironmentLevel, "baz", "env")
+	assert.NoError(t, store.Close())
+
+	v, _ = openJournal(t, path, opts)
+	assert.Equal(t, ConfigMap{"foo": "file"}, v.Level(FileLevel))
+	assert.Equal(t, ConfigMap{"bar": "override"}, v.Level(OverrideLevel))
+	assert.Nil(t, v.Level(EnvironmentLevel))
+
+	// records for levels which are no longer journaled are ignored
+	v, _ = openJournal(t, path, JournalOptions{})
+	assert.Nil(t, v.Level(FileLevel))
+	assert.Equal(t, "override", v.Get("bar"))
+}
+
+func TestJournalStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.journal")
+
+	v, store := openJournal(t, path, JournalOptions{CompactAfter: 5})
+	for _, level := range []string{"debug", "info", "warn", "error"} {
+		v.SetOverride("log.level", level)
+	}
+	assert.Len(t, journalLines(t, path), 4)
+
+	v.SetOverride("db.host", "example.com")
+	assert.Equal(t, []string{
+		`{"op":"set","level":99,"key":"db.host","value":"example.com"}`,
+		`{"op":"set","level":99,"key":"log.level","value":"error"}`,
+	}, journalLines(t, path))
+
+	v.Unset(OverrideLevel, "log.level")
+	assert.NoError(t, store.Compact())
+	assert.Equal(t, []string{
+		`{"op":"set","level":99,"key":"db.host","value":"example.com"}`,
+	}, journalLines(t, path))
+
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1, "no temporary files are left behind")
+}
+
+func TestJournalStoreCrashRecovery(t *testing.T) {
+	t.Run("should ignore a partially written final record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "overrides.journal")
+		contents := `{"op":"set","level":99,"key":"foo","value":"bar"}` + "\n" +
+			`{"op":"set","level":99,"key":"foo","val`
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+
+		v, _ := openJournal(t, path, JournalOptions{})
+		assert.Equal(t, "bar", v.Get("foo"))
+		assert.Equal(t, []string{`{"op":"set","level":99,"key":"foo","value":"bar"}`}, journalLines(t, path))
+	})
+
+	t.Run("should error on a corrupt record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "overrides.journal")
+		contents := `{"op":"set","level":99,"key":"foo","value":"bar"}` + "\n" +
+			`{"op":"remove","level":99,"key":"foo"}` + "\n" +
+			`{"op":"set","level":99,"key":"foo","value":"baz"}` + "\n"
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+
+		_, err := NewJournalStore(NewDefaultConfigStore(), path)
+		var journalErr *JournalError
+		assert.True(t, errors.As(err, &journalErr))
+		assert.Equal(t, 2, journalErr.Line)
+		assert.EqualError(t, err, "venom: "+path+`: line 2: unknown operation "remove"`)
+	})
+}
+
+func TestJournalStoreNestedConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.journal")
+
+	t.Run("should reject keys nested beneath scalar values", func(t *testing.T) {
+		v, store := openJournal(t, path, JournalOptions{})
+		v.SetOverride("db", "x")
+		assert.NotPanics(t, func() { v.SetOverride("db.host", "y") })
+		assert.Error(t, store.Err())
+		assert.Equal(t, "x", v.Get("db"))
+		assert.NoError(t, store.Close())
+
+		v, _ = openJournal(t, path, JournalOptions{})
+		assert.Equal(t, "x", v.Get("db"))
+	})
+
+	t.Run("should error on a conflicting record", func(t *testing.T) {
+		contents := `{"op":"set","level":99,"key":"db","value":"x"}` + "\n" +
+			`{"op":"set","level":99,"key":"db.host","value":"y"}` + "\n"
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+
+		var err error
+		assert.NotPanics(t, func() { _, err = NewJournalStore(NewDefaultConfigStore(), path) })
+		var journalErr *JournalError
+		if assert.True(t, errors.As(err, &journalErr), "%v", err) {
+			assert.Equal(t, 2, journalErr.Line)
+		}
+	})
+}
+
+func TestJournalStoreClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.journal")
+	v, store := openJournal(t, path, JournalOptions{})
+	assert.NoError(t, store.Close())
+
+	v.SetOverride("foo", "bar")
+	assert.Error(t, store.Err())
+	assert.Nil(t, v.Get("foo"), "writes which are not journaled are not applied")
+
+	// non-journaled levels are unaffected
+	v.SetDefault("foo", "default")
+	assert.Equal(t, "default", v.Get("foo"))
+
+	_, err := os.Stat(path)
+	assert.NoError(t, err)
+}
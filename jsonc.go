@@ -0,0 +1,172 @@
+package venom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	jsoncKey = "jsonc"
+	json5Key = "json5"
+)
+
+// JSONCLoader is an IOFileLoader which loads JSON config data that may also
+// contain "//" and "/* */" comments, trailing commas in objects and arrays,
+// and single-quoted strings.
+//
+// JSONCLoader is registered for the ".jsonc" and ".json5" extensions. It may
+// also be used for plain ".json" files by calling
+//
+//	RegisterExtension("json", JSONCLoader)
+func JSONCLoader(r io.Reader) (map[string]interface{}, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	output, offsets, err := stripJSONC(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkJSONLimits(output, limitsOf(r)); err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(output, &data); err != nil {
+		return nil, jsonLoadError(jsoncKey, input, err, func(offset int64) int {
+			return originalOffset(offsets, len(input), offset)
+		})
+	}
+	return data, nil
+}
+
+// stripJSONC converts the JSONC input into standard JSON. Alongside the
+// converted document, a slice mapping every byte of the output back to its
+// offset in the input is returned so that errors can be reported against the
+// original document.
+func stripJSONC(input []byte) ([]byte, []int, error) {
+	output := make([]byte, 0, len(input))
+	offsets := make([]int, 0, len(input))
+	emit := func(b byte, at int) {
+		output = append(output, b)
+		offsets = append(offsets, at)
+	}
+
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '"':
+			// copy double-quoted strings verbatim, including escape sequences
+			emit(c, i)
+			for i++; i < len(input); i++ {
+				emit(input[i], i)
+				if input[i] == '\\' && i+1 < len(input) {
+					i++
+					emit(input[i], i)
+				} else if input[i] == '"' {
+					break
+				}
+			}
+		case c == '\'':
+			// convert single-quoted strings into double-quoted strings
+			emit('"', i)
+			for i++; i < len(input); i++ {
+				switch input[i] {
+				case '\\':
+					if i+1 < len(input) && input[i+1] == '\'' {
+						i++
+						emit('\'', i)
+						continue
+					}
+					emit(input[i], i)
+					if i+1 < len(input) {
+						i++
+						emit(input[i], i)
+					}
+					continue
+				case '"':
+					emit('\\', i)
+					emit('"', i)
+					continue
+				case '\'':
+					emit('"', i)
+				default:
+					emit(input[i], i)
+					continue
+				}
+				break
+			}
+		case c == '/' && i+1 < len(input) && input[i+1] == '/':
+			// replace line comments with whitespace
+			for ; i < len(input) && input[i] != '\n'; i++ {
+				emit(' ', i)
+			}
+			if i < len(input) {
+				emit('\n', i)
+			}
+		case c == '/' && i+1 < len(input) && input[i+1] == '*':
+			// replace block comments with whitespace, retaining any newlines
+			end := bytes.Index(input[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, nil, newLoadError(jsoncKey, input, i, fmt.Errorf("unterminated block comment"))
+			}
+			for stop := i + 2 + end + 2; i < stop; i++ {
+				if input[i] == '\n' {
+					emit('\n', i)
+				} else {
+					emit(' ', i)
+				}
+			}
+			i--
+		default:
+			emit(c, i)
+		}
+	}
+
+	stripTrailingCommas(output)
+	return output, offsets, nil
+}
+
+// stripTrailingCommas replaces any commas which directly precede the end of an
+// object or an array with whitespace
+func stripTrailingCommas(data []byte) {
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+		case ',':
+			next := i + 1
+			for next < len(data) && isJSONWhitespace(data[next]) {
+				next++
+			}
+			if next < len(data) && (data[next] == '}' || data[next] == ']') {
+				data[i] = ' '
+			}
+		}
+	}
+}
+
+func isJSONWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// originalOffset maps an encoding/json error offset, which is the number of
+// bytes read before the error occurred, back to an offset in the original
+// input
+func originalOffset(offsets []int, inputLen int, offset int64) int {
+	index := int(offset) - 1
+	switch {
+	case index < 0:
+		return 0
+	case index >= len(offsets):
+		return inputLen
+	}
+	return offsets[index]
+}
@@ -0,0 +1,106 @@
+package venom
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONCLoader(t *testing.T) {
+	testIO := []struct {
+		tc     string
+		input  string
+		expect map[string]interface{}
+		line   int
+		column int
+	}{
+		{
+			tc:     "should load plain JSON",
+			input:  `{"foo": "bar", "level": 5.0}`,
+			expect: map[string]interface{}{"foo": "bar", "level": 5.0},
+		},
+		{
+			tc:     "should strip line comments",
+			input:  "{\n// comment\n\"foo\": \"bar\" // another\n}",
+			expect: map[string]interface{}{"foo": "bar"},
+		},
+		{
+			tc:     "should strip block comments",
+			input:  "{/* a\nmulti-line\ncomment */\"foo\": /* inline */ \"bar\"}",
+			expect: map[string]interface{}{"foo": "bar"},
+		},
+		{
+			tc:     "should not strip comment markers inside strings",
+			input:  `{"url": "http://example.com/*path*/", 'glob': '//*'}`,
+			expect: map[string]interface{}{"url": "http://example.com/*path*/", "glob": "//*"},
+		},
+		{
+			tc:     "should allow trailing commas",
+			input:  "{\"hosts\": [\"a\", \"b\", ], \"log\": {\"level\": \"info\",\n// comment\n},}",
+			expect: map[string]interface{}{"hosts": []interface{}{"a", "b"}, "log": map[string]interface{}{"level": "info"}},
+		},
+		{
+			tc:     "should not strip commas inside strings",
+			input:  `{"foo": ",}"}`,
+			expect: map[string]interface{}{"foo": ",}"},
+		},
+		{
+			tc:     "should allow single-quoted strings",
+			input:  `{'foo': 'it\'s "quoted"', 'bar': 'a\nb'}`,
+			expect: map[string]interface{}{"foo": `it's "quoted"`, "bar": "a\nb"},
+		},
+		{
+			tc:     "should report the line and column of syntax errors",
+			input:  "{\n  // comment\n  'foo': 'bar',\n  \"level\" 5\n}",
+			line:   4,
+			column: 11,
+		},
+		{
+			tc:     "should report unterminated block comments",
+			input:  "{\n  /* comment\n}",
+			line:   2,
+			column: 3,
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			actual, err := JSONCLoader(strings.NewReader(test.input))
+			if test.line > 0 {
+				var syntaxErr *LoadError
+				if assert.True(t, errors.As(err, &syntaxErr), "%v", err) {
+					assert.Equal(t, test.line, syntaxErr.Line)
+					assert.Equal(t, test.column, syntaxErr.Column)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
+
+func TestJSONCLoaderUnwrapsSyntaxError(t *testing.T) {
+	_, err := JSONCLoader(strings.NewReader(`{"foo": }`))
+
+	var jsonErr *json.SyntaxError
+	assert.True(t, errors.As(err, &jsonErr))
+}
+
+func TestLoadJSONCFile(t *testing.T) {
+	v := New()
+	assert.NoError(t, v.LoadFile("testdata/jsonc/config.jsonc"))
+
+	st := v.Store.(*DefaultConfigStore)
+	assert.EqualValues(t, ConfigMap{
+		"foo": "bar",
+		"log": ConfigMap{
+			"level": "info",
+			"file":  "/usr/local/example.log",
+		},
+		"hosts": []interface{}{"a", "b"},
+	}, st.config[FileLevel])
+}
@@ -0,0 +1,169 @@
+package venom
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// KeyFileDataDir is the name of the symlink which Kubernetes atomically swaps to
+// point at the current contents of a mounted ConfigMap or Secret volume
+const KeyFileDataDir = "..data"
+
+// KeyFileSeparators are the separators within the file names of a key-per-file
+// directory which denote nesting, ie both "db__host" and "db.host" are loaded as
+// the key "db.host"
+var KeyFileSeparators = []string{"__", "."}
+
+// LoadKeyPerFileDirectory loads a directory containing one file per config key,
+// such as a Kubernetes ConfigMap or Secret mounted as a volume, into the
+// specified ConfigLevel.
+//
+// The name of each file is its key, with any of the KeyFileSeparators denoting
+// nesting, and the contents of the file, with any trailing newlines removed, is
+// its string value. Sub-directories and hidden files, such as the "..data"
+// symlink maintained by Kubernetes, are skipped.
+//
+// If the directory contains a KeyFileDataDir symlink, every file is read from
+// the directory it currently points to, meaning that the keys are always read
+// from a single consistent snapshot even while Kubernetes is swapping in an
+// update. Like other directories, the directory is re-read by Reload and
+// watched by WatchFiles.
+//
+// The MaxFileSize set via SetLimits applies to each key file, and if signature
+// verification is enabled, each key file must be signed by a trusted key, ie
+// "db__host" must be signed by "db__host.sig".
+func (v *Venom) LoadKeyPerFileDirectory(dir string, level ConfigLevel) error {
+	return v.loadSource(&keyFileSource{dir: dir, at: level})
+}
+
+// A keyFileSource is a directory containing one file per config key
+type keyFileSource struct {
+	dir string
+	at  ConfigLevel
+}
+
+func (s *keyFileSource) level() ConfigLevel {
+	return s.at
+}
+
+func (s *keyFileSource) read(v *Venom) ([]*fileLayer, error) {
+	root, names, err := s.files(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// key files, and their signatures, are read from the root, but are
+	// reported by their path within the loaded directory
+	open := func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(root, filepath.Base(name)))
+	}
+
+	data := make(ConfigMap)
+	for _, name := range names {
+		contents, err := v.readVerifiedFile(filepath.Join(s.dir, name), open)
+		if err != nil {
+			return nil, err
+		}
+
+		value := strings.TrimRight(string(contents), "\r\n")
+		if err := setKeyFile(data, keyFileKeys(name), value); err != nil {
+			return nil, &LoadError{Path: filepath.Join(s.dir, name), Err: err}
+		}
+	}
+
+	if err := v.Limits().check(data); err != nil {
+		return nil, &LoadError{Path: s.dir, Err: err}
+	}
+	return []*fileLayer{newFileLayer(s.at, fileLoad{name: s.dir, base: s.dir}, data)}, nil
+}
+
+func (s *keyFileSource) paths(v *Venom) []string {
+	_, names, _ := s.files(v)
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		paths = append(paths, filepath.Join(s.dir, name))
+	}
+	return paths
+}
+
+// files returns the directory that the key files should be read from, along
+// with the lexically ordered names of the key files within it. If signature
+// verification is enabled, the detached signatures of the key files are not
+// themselves key files.
+func (s *keyFileSource) files(v *Venom) (string, []string, error) {
+	root := s.dir
+	if resolved, err := filepath.EvalSymlinks(filepath.Join(s.dir, KeyFileDataDir)); err == nil {
+		root = resolved
+	}
+
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if v.signatures.enabled() && strings.HasSuffix(name, SignatureExtension) {
+			continue
+		}
+
+		// stat the entry to follow any symlinks to the key files
+		info, err := os.Stat(filepath.Join(root, name))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return root, names, nil
+}
+
+// keyFileKeys splits the provided file name into its nested keys
+func keyFileKeys(name string) []string {
+	keys := []string{name}
+	for _, sep := range KeyFileSeparators {
+		var split []string
+		for _, key := range keys {
+			split = append(split, strings.Split(key, sep)...)
+		}
+		keys = split
+	}
+	return keys
+}
+
+// setKeyFile inserts the provided value into the nested keyspace, returning an
+// error if the keyspace conflicts with a value loaded from another file
+func setKeyFile(data ConfigMap, keys []string, value string) error {
+	for index, key := range keys {
+		if key == "" {
+			return fmt.Errorf("invalid key %q", strings.Join(keys, Delim))
+		}
+
+		if index == len(keys)-1 {
+			if _, ok := data[key]; ok {
+				return fmt.Errorf("key %q is set by multiple files", strings.Join(keys, Delim))
+			}
+			data[key] = value
+			return nil
+		}
+
+		if _, ok := data[key]; !ok {
+			data[key] = make(ConfigMap)
+		}
+		nested, ok := data[key].(ConfigMap)
+		if !ok {
+			return fmt.Errorf("key %q conflicts with key %q", strings.Join(keys, Delim), strings.Join(keys[:index+1], Delim))
+		}
+		data = nested
+	}
+	return nil
+}
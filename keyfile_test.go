@@ -0,0 +1,122 @@
+package venom
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKeyPerFileDirectory(t *testing.T) {
+	testIO := []struct {
+		tc     string
+		files  map[string]string
+		err    string
+		expect ConfigMap
+	}{
+		{
+			tc: "should map file names to keys",
+			files: map[string]string{
+				"username":      "admin\n",
+				"db__host":      "localhost\r\n",
+				"db.port":       "5432",
+				"log__out.file": "/var/log/app.log\n\n",
+				".hidden":       "skipped",
+				"sub/ignored":   "skipped",
+			},
+			expect: ConfigMap{
+				"username": "admin",
+				"db":       ConfigMap{"host": "localhost", "port": "5432"},
+				"log":      ConfigMap{"out": ConfigMap{"file": "/var/log/app.log"}},
+			},
+		},
+		{
+			tc: "should keep leading and inner whitespace",
+			files: map[string]string{
+				"cert": "  line one\nline two\n",
+			},
+			expect: ConfigMap{"cert": "  line one\nline two"},
+		},
+		{
+			tc: "should error on conflicting keys",
+			files: map[string]string{
+				"db":       "localhost",
+				"db__host": "localhost",
+			},
+			err: `db__host: key "db.host" conflicts with key "db"`,
+		},
+		{
+			tc: "should error on duplicate keys",
+			files: map[string]string{
+				"db.host":  "localhost",
+				"db__host": "localhost",
+			},
+			err: `db__host: key "db.host" is set by multiple files`,
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, test.files)
+
+			v := New()
+			err := v.LoadKeyPerFileDirectory(dir, FileLevel)
+			if test.err != "" {
+				var loadErr *LoadError
+				assert.True(t, errors.As(err, &loadErr))
+				assert.EqualError(t, err, "venom: "+filepath.Join(dir, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, v.Level(FileLevel))
+		})
+	}
+}
+
+func TestLoadKeyPerFileDirectoryLimits(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"small": "value",
+		"large": strings.Repeat("a", 64),
+	})
+
+	v := New()
+	v.SetLimits(LoadLimits{MaxFileSize: 32})
+	err := v.LoadKeyPerFileDirectory(dir, FileLevel)
+
+	var limitErr *LimitError
+	assert.True(t, errors.As(err, &limitErr), "%v", err)
+	var loadErr *LoadError
+	if assert.True(t, errors.As(err, &loadErr)) {
+		assert.Equal(t, filepath.Join(dir, "large"), loadErr.Path)
+	}
+	assert.Equal(t, 0, v.Size())
+}
+
+func TestLoadSignedKeyPerFileDirectory(t *testing.T) {
+	trusted, trustedKey := generateKey(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"username": "admin\n",
+		"db__host": "localhost\n",
+	})
+	assert.NoError(t, SignFile(trustedKey, filepath.Join(dir, "username")))
+
+	v := New()
+	v.AddTrustedKeys(trusted)
+	v.SetStrictSignatures(true)
+	err := v.LoadKeyPerFileDirectory(dir, FileLevel)
+	assert.True(t, errors.Is(err, ErrUnsigned), "%v", err)
+	assert.Equal(t, 0, v.Size())
+
+	assert.NoError(t, SignFile(trustedKey, filepath.Join(dir, "db__host")))
+	assert.NoError(t, v.LoadKeyPerFileDirectory(dir, FileLevel))
+	assert.Equal(t, ConfigMap{
+		"username": "admin",
+		"db":       ConfigMap{"host": "localhost"},
+	}, v.Level(FileLevel))
+}
@@ -0,0 +1,104 @@
+//go:build !windows
+// +build !windows
+
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mountKeyFiles lays out the provided files in dir in the same manner as a
+// Kubernetes ConfigMap volume, with each key symlinked through the "..data"
+// symlink into a timestamped directory. Calling it again atomically swaps the
+// "..data" symlink to a new directory.
+func mountKeyFiles(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+	snapshot := "..2024_01_01_00_00_00." + version
+	writeFiles(t, filepath.Join(dir, snapshot), files)
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(snapshot, tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, KeyFileDataDir)); err != nil {
+		t.Fatal(err)
+	}
+
+	for name := range files {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err == nil {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(KeyFileDataDir, name), link); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadKeyPerFileDirectoryMount(t *testing.T) {
+	dir := t.TempDir()
+	mountKeyFiles(t, dir, "1", map[string]string{
+		"db__host": "localhost\n",
+		"db__user": "admin\n",
+	})
+
+	v := New()
+	assert.NoError(t, v.LoadKeyPerFileDirectory(dir, OverrideLevel))
+	assert.Equal(t, ConfigMap{"db": ConfigMap{"host": "localhost", "user": "admin"}}, v.Level(OverrideLevel))
+
+	// keys are read from the snapshot that "..data" points to, so keys whose
+	// symlinks have not yet been removed after a swap are not loaded
+	mountKeyFiles(t, dir, "2", map[string]string{
+		"db__host": "example.com\n",
+	})
+	assert.NoError(t, v.Reload())
+	assert.Equal(t, ConfigMap{"db": ConfigMap{"host": "example.com"}}, v.Level(OverrideLevel))
+}
+
+func TestWatchKeyPerFileDirectory(t *testing.T) {
+	dir := t.TempDir()
+	mountKeyFiles(t, dir, "1", map[string]string{
+		"log__level": "info\n",
+	})
+
+	store, clear := NewSubscriptionStoreWithSize(NewSafeConfigStore(), 16)
+	defer clear()
+	events := store.Subscribe("log")
+
+	v := NewWithStore(store)
+	assert.NoError(t, v.LoadKeyPerFileDirectory(dir, FileLevel))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := v.WatchFiles(ctx, 10*time.Millisecond)
+
+	mountKeyFiles(t, dir, "2", map[string]string{
+		"log__level": "debug\n",
+		"log__file":  "/var/log/app.log\n",
+	})
+
+	var received []Event
+	for len(received) < 2 {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case err := <-errs:
+			t.Fatalf("unexpected reload error: %s", err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+	}
+	assert.Equal(t, []Event{
+		{Key: "log.file", Value: "/var/log/app.log"},
+		{Key: "log.level", Value: "debug"},
+	}, received)
+
+	cancel()
+	for range errs {
+	}
+}
@@ -0,0 +1,116 @@
+package venom
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A KeyMapper maps the segments of a config key, ie ["log", "level"] for
+// "log.level", to the name used to look the key up from a source such as the
+// environment or a FlagSet.
+//
+// Unlike a KeyTranslator, a KeyMapper operates on whole strings, meaning that
+// it is able to handle multi-byte runes, insert separators between words and
+// drop characters.
+type KeyMapper func(segments []string) string
+
+// TranslatorMapper adapts the provided KeyTranslator into a KeyMapper which
+// joins the segments with sep before translating each byte of the result
+func TranslatorMapper(sep string, translator KeyTranslator) KeyMapper {
+	return func(segments []string) string {
+		key := []byte(strings.Join(segments, sep))
+		for index, char := range key {
+			key[index] = translator(char)
+		}
+		return string(key)
+	}
+}
+
+// ScreamingSnakeMapper is a KeyMapper which splits each segment into words and
+// joins them as upper case words separated by underscores, ie "httpServer.
+// max-conns" is mapped to "HTTP_SERVER_MAX_CONNS".
+func ScreamingSnakeMapper(segments []string) string {
+	words := splitSegments(segments)
+	for index, word := range words {
+		words[index] = strings.ToUpper(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// KebabMapper is a KeyMapper which splits each segment into words and joins
+// them as lower case words separated by hyphens, ie "httpServer.max_conns" is
+// mapped to "http-server-max-conns".
+func KebabMapper(segments []string) string {
+	words := splitSegments(segments)
+	for index, word := range words {
+		words[index] = strings.ToLower(word)
+	}
+	return strings.Join(words, "-")
+}
+
+// CamelMapper is a KeyMapper which splits each segment into words and joins
+// them in camelCase, ie "http_server.max-conns" is mapped to
+// "httpServerMaxConns".
+func CamelMapper(segments []string) string {
+	words := splitSegments(segments)
+	for index, word := range words {
+		word = strings.ToLower(word)
+		if index > 0 {
+			word = upperFirst(word)
+		}
+		words[index] = word
+	}
+	return strings.Join(words, "")
+}
+
+// upperFirst converts the first rune of the provided string to title case
+func upperFirst(s string) string {
+	for index, r := range s {
+		return string(unicode.ToTitle(r)) + s[index+len(string(r)):]
+	}
+	return s
+}
+
+// splitSegments splits each of the provided segments into words
+func splitSegments(segments []string) []string {
+	var words []string
+	for _, segment := range segments {
+		words = append(words, splitWords(segment)...)
+	}
+	return words
+}
+
+// splitWords splits the provided string into words. Words are separated by any
+// rune which is not a letter or digit, and by changes in case, ie "HTTPServer"
+// and "http_server" are both split into "HTTP" or "http" and "Server" or
+// "server".
+func splitWords(s string) []string {
+	runes := []rune(s)
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	for index, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := word[len(word)-1]
+			nextIsLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			// split "logLevel" before "L", and "HTTPServer" before "S"
+			if !unicode.IsUpper(prev) || nextIsLower {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,115 @@
+package venom
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyMappers(t *testing.T) {
+	testIO := []struct {
+		tc       string
+		mapper   KeyMapper
+		segments []string
+		expect   string
+	}{
+		{
+			tc:       "should translate bytes with a TranslatorMapper",
+			mapper:   TranslatorMapper(EnvSeparator, DefaultEnvironmentVariableKeyTranslator),
+			segments: []string{"log", "level"},
+			expect:   "LOG_LEVEL",
+		},
+		{
+			tc:       "should join with the provided separator",
+			mapper:   TranslatorMapper(FlagSeparator, NoOpKeyTranslator),
+			segments: []string{"log", "level"},
+			expect:   "log-level",
+		},
+		{
+			tc:       "should split camelCase into SCREAMING_SNAKE",
+			mapper:   ScreamingSnakeMapper,
+			segments: []string{"httpServer", "maxConns"},
+			expect:   "HTTP_SERVER_MAX_CONNS",
+		},
+		{
+			tc:       "should split acronyms into SCREAMING_SNAKE",
+			mapper:   ScreamingSnakeMapper,
+			segments: []string{"HTTPServer", "max-conns"},
+			expect:   "HTTP_SERVER_MAX_CONNS",
+		},
+		{
+			tc:       "should upper case multi-byte runes",
+			mapper:   ScreamingSnakeMapper,
+			segments: []string{"größe", "überLimit"},
+			expect:   "GRÖßE_ÜBER_LIMIT",
+		},
+		{
+			tc:       "should drop empty words",
+			mapper:   ScreamingSnakeMapper,
+			segments: []string{"__log__", "", "level"},
+			expect:   "LOG_LEVEL",
+		},
+		{
+			tc:       "should map to kebab-case",
+			mapper:   KebabMapper,
+			segments: []string{"httpServer", "max_conns"},
+			expect:   "http-server-max-conns",
+		},
+		{
+			tc:       "should keep digits with their word",
+			mapper:   KebabMapper,
+			segments: []string{"apiV2", "port"},
+			expect:   "api-v2-port",
+		},
+		{
+			tc:       "should map to camelCase",
+			mapper:   CamelMapper,
+			segments: []string{"http_server", "max-conns"},
+			expect:   "httpServerMaxConns",
+		},
+		{
+			tc:       "should map multi-byte runes to camelCase",
+			mapper:   CamelMapper,
+			segments: []string{"ÉTAT", "élevé"},
+			expect:   "étatÉlevé",
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.mapper(test.segments))
+		})
+	}
+}
+
+func TestEnvironmentKeyMapper(t *testing.T) {
+	t.Setenv("MYAPP_HTTP_SERVER_MAX_CONNS", "10")
+	t.Setenv("MYAPP_HTTPSERVER_MAXCONNS", "ignored")
+
+	r := &EnvironmentVariableResolver{
+		Prefix: "myapp",
+		Mapper: ScreamingSnakeMapper,
+		// the Mapper takes precedence over the Translator
+		Translator: NoOpKeyTranslator,
+	}
+
+	actual, ok := r.Resolve([]string{"httpServer", "maxConns"}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "10", actual)
+}
+
+func TestFlagsetKeyMapper(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("http-server-max-conns", "1", "max connections")
+
+	r := &FlagsetResolver{
+		Flags:     fs,
+		Arguments: []string{"-http-server-max-conns=10"},
+		Mapper:    KebabMapper,
+	}
+
+	actual, ok := r.Resolve([]string{"httpServer", "maxConns"}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "10", actual)
+}
@@ -0,0 +1,247 @@
+package venom
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// A SubLevelFunc maps the base name of a config file to the sub-level that the
+// file is loaded into within its ConfigLevel. Files with a higher sub-level
+// take precedence over files with a lower sub-level in the same ConfigLevel.
+type SubLevelFunc func(name string) int
+
+// FileSubLevel is the SubLevelFunc used to determine the sub-level of every
+// file loaded into Venom. The default is NumericPrefixSubLevel.
+var FileSubLevel SubLevelFunc = NumericPrefixSubLevel
+
+// NumericPrefixSubLevel is a SubLevelFunc which uses the numeric prefix of a
+// file name as its sub-level. The prefix must be separated from the rest of
+// the name by a '-', '_' or '.' character, ie, "10-base.json" has a sub-level
+// of 10 and "90_local.json" has a sub-level of 90. A '.' only separates a
+// prefix when it is followed by more than the extension of the file, meaning
+// that "2024.json" has no prefix while "5.config.json" has a sub-level of 5.
+// Files without a numeric prefix, or with a prefix too large to be represented
+// as an int, have a sub-level of 0.
+func NumericPrefixSubLevel(name string) int {
+	const maxLevel = int(^uint(0) >> 1)
+
+	var level int
+	for index, char := range name {
+		switch {
+		case char >= '0' && char <= '9':
+			digit := int(char - '0')
+			if level > (maxLevel-digit)/10 {
+				return 0
+			}
+			level = level*10 + digit
+		case index > 0 && (char == '-' || char == '_'):
+			return level
+		case index > 0 && char == '.':
+			rest := name[index:]
+			if rest == configExt(rest) {
+				return 0
+			}
+			return level
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+// subLevel returns the sub-level of the file at the provided path
+func subLevel(name string) int {
+	return FileSubLevel(filepath.Base(name))
+}
+
+// sortBySubLevel orders the provided file paths by their sub-level, retaining
+// the existing order of files that share a sub-level
+func sortBySubLevel(files []string) {
+	sort.SliceStable(files, func(i, j int) bool {
+		return subLevel(files[i]) < subLevel(files[j])
+	})
+}
+
+// A fileLayer records the config data loaded from a single file into a
+// sub-level of a ConfigLevel
+type fileLayer struct {
+	name     string
+	level    ConfigLevel
+	subLevel int
+
+	// profile is the name of the profile that this file is an overlay for,
+	// and rank is the position of that profile in the list of active profiles,
+	// starting at 1. Files which are not profile overlays have a rank of 0.
+	profile string
+	rank    int
+
+	data ConfigMap
+
+	// source is the file or directory which this file was loaded as part of,
+	// if the file can be reloaded
+	source configSource
+}
+
+// newFileLayer returns a fileLayer for the config data loaded from the named
+// file. The data is copied into a nested ConfigMap, ensuring that it is not
+// shared with the ConfigStore it is merged into.
+func newFileLayer(level ConfigLevel, load fileLoad, data map[string]interface{}) *fileLayer {
+	return &fileLayer{
+		name:     load.name,
+		level:    level,
+		subLevel: subLevel(load.base),
+		profile:  load.profile,
+		rank:     load.rank,
+		data:     make(ConfigMap).merge(data),
+	}
+}
+
+// readLayers reads the file described by load, along with any files that it
+// includes or extends, returning a fileLayer for each in increasing order of
+// precedence. Included files share the precedence of the file including them.
+func (v *Venom) readLayers(level ConfigLevel, load fileLoad) ([]*fileLayer, error) {
+	files, err := v.readFileWithIncludes(load.name)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make([]*fileLayer, 0, len(files))
+	for _, file := range files {
+		included := load
+		included.name = file.name
+		layers = append(layers, newFileLayer(level, included, file.data))
+	}
+	return layers, nil
+}
+
+// precedes returns true if the layer l has a lower precedence than the layer o
+// within the same ConfigLevel
+func (l *fileLayer) precedes(o *fileLayer) bool {
+	if l.subLevel != o.subLevel {
+		return l.subLevel < o.subLevel
+	}
+	return l.rank < o.rank
+}
+
+// fileLayers tracks the files which have been loaded into a Venom instance so
+// that the precedence of their sub-levels can be maintained
+type fileLayers struct {
+	// update serializes writes of loaded files into the ConfigStore
+	update sync.Mutex
+
+	mu      sync.Mutex
+	layers  []*fileLayer
+	sources []configSource
+}
+
+// mergeLayer merges the data loaded from a file into its ConfigLevel. Any
+// previously loaded files within the same ConfigLevel which have a higher
+// precedence are re-applied afterwards, so that they retain their precedence
+// regardless of the order in which files are loaded.
+func (v *Venom) mergeLayer(layer *fileLayer) {
+	v.files.update.Lock()
+	defer v.files.update.Unlock()
+
+	v.files.mu.Lock()
+	v.files.layers = appendLayer(v.files.layers, layer)
+	var higher []*fileLayer
+	for _, existing := range v.files.sorted() {
+		if existing.level == layer.level && layer.precedes(existing) {
+			higher = append(higher, existing)
+		}
+	}
+	v.files.mu.Unlock()
+
+	v.Merge(layer.level, layer.data)
+	for _, existing := range higher {
+		v.Merge(layer.level, existing.data)
+	}
+}
+
+// appendLayer appends the provided layer to layers, replacing any layer
+// previously loaded from the same file into the same ConfigLevel.
+//
+// Layers which were not loaded from a named file, ie via LoadReader, are never
+// replaced. Instead, each is folded into the previous unnamed layer with the
+// same ConfigLevel and precedence, as long as no named layer which shares that
+// precedence was loaded in between, so that repeated loads do not accumulate
+// layers.
+func appendLayer(layers []*fileLayer, layer *fileLayer) []*fileLayer {
+	kept := layers[:0]
+	for _, existing := range layers {
+		if layer.name == "" || existing.name != layer.name || existing.level != layer.level {
+			kept = append(kept, existing)
+		}
+	}
+	return foldUnnamed(append(kept, layer))
+}
+
+// foldUnnamed folds every layer which was not loaded from a named file into
+// the preceding layer with the same ConfigLevel and precedence, if that layer
+// was not loaded from a named file either. The order in which the remaining
+// layers are applied is unchanged.
+func foldUnnamed(layers []*fileLayer) []*fileLayer {
+	kept := layers[:0]
+	for _, layer := range layers {
+		if layer.name == "" {
+			if index := lastPeer(kept, layer); index >= 0 && kept[index].name == "" {
+				folded := *kept[index]
+				folded.data = make(ConfigMap).merge(kept[index].data).merge(layer.data)
+				kept[index] = &folded
+				continue
+			}
+		}
+		kept = append(kept, layer)
+	}
+	return kept
+}
+
+// lastPeer returns the index of the last of the provided layers which shares
+// the ConfigLevel and precedence of the provided layer, or -1 if there is none
+func lastPeer(layers []*fileLayer, layer *fileLayer) int {
+	for index := len(layers) - 1; index >= 0; index-- {
+		existing := layers[index]
+		if existing.level == layer.level && !existing.precedes(layer) && !layer.precedes(existing) {
+			return index
+		}
+	}
+	return -1
+}
+
+// levelData returns the config data of every provided layer loaded into the
+// provided ConfigLevel, merged in order of precedence
+func levelData(layers []*fileLayer, level ConfigLevel) ConfigMap {
+	data := make(ConfigMap)
+	for _, layer := range sortLayers(layers) {
+		if layer.level == level {
+			data.merge(layer.data)
+		}
+	}
+	return data
+}
+
+// sorted returns the loaded layers ordered by precedence, retaining the order
+// in which files were loaded for files that share a precedence
+func (f *fileLayers) sorted() []*fileLayer {
+	return sortLayers(f.layers)
+}
+
+// sortLayers returns a copy of the provided layers ordered by precedence,
+// retaining the existing order of layers that share a precedence
+func sortLayers(layers []*fileLayer) []*fileLayer {
+	sorted := make([]*fileLayer, len(layers))
+	copy(sorted, layers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].precedes(sorted[j])
+	})
+	return sorted
+}
+
+// reset forgets all loaded files
+func (f *fileLayers) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.layers = nil
+	f.sources = nil
+}
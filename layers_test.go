@@ -0,0 +1,89 @@
+package venom
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumericPrefixSubLevel(t *testing.T) {
+	testIO := []struct {
+		name   string
+		expect int
+	}{
+		{"10-base.json", 10},
+		{"90_local.json", 90},
+		{"5.config.json", 5},
+		{"007-bond.json", 7},
+		{"config.json", 0},
+		{"10base.json", 0},
+		{"-10-base.json", 0},
+		{"10", 0},
+		{"2024.json", 0},
+		{"2024.json.gz", 0},
+		{"5.config.json.gz", 5},
+		{"99999999999999999999-big.json", 0},
+		{strconv.Itoa(int(^uint(0)>>1)) + "-max.json", int(^uint(0) >> 1)},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, NumericPrefixSubLevel(test.name))
+		})
+	}
+}
+
+func TestFileSubLevelPrecedence(t *testing.T) {
+	t.Run("should retain sub-level precedence regardless of load order", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadFile("testdata/layered/90-local.json"))
+		assert.NoError(t, v.LoadFile("testdata/layered/10-base.json"))
+
+		assert.Equal(t, "local", v.Get("foo"))
+		assert.Equal(t, "debug", v.Get("log.level"))
+		assert.Equal(t, "/var/log/app.log", v.Get("log.file"))
+		assert.Equal(t, 10.0, v.Get("level"))
+	})
+
+	t.Run("should use a custom SubLevelFunc", func(t *testing.T) {
+		defer func(f SubLevelFunc) { FileSubLevel = f }(FileSubLevel)
+		FileSubLevel = func(name string) int { return -NumericPrefixSubLevel(name) }
+
+		v := New()
+		assert.NoError(t, v.LoadDirectory("testdata/layered", false))
+		assert.Equal(t, "defaults", v.Get("foo"))
+	})
+
+	t.Run("should forget loaded files when cleared", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadFile("testdata/layered/90-local.json"))
+		v.Clear()
+		assert.NoError(t, v.LoadFile("testdata/layered/10-base.json"))
+		assert.Equal(t, "base", v.Get("foo"))
+	})
+}
+
+func TestUnnamedLayers(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"config.json": `{"foo": "file", "bar": "file"}`})
+	name := filepath.Join(dir, "config.json")
+
+	v := New()
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, v.LoadBytes([]byte(`{"foo": "bytes"}`), "json", FileLevel))
+		assert.NoError(t, v.LoadFile(name))
+	}
+	assert.NoError(t, v.LoadBytes([]byte(`{"bar": "bytes"}`), "json", FileLevel))
+	assert.NoError(t, v.LoadEnvironmentFrom(EnvironmentMap(map[string]string{"APP_BAZ": "env"}), "app", FileLevel))
+	assert.Len(t, v.files.layers, 3, "repeated loads do not accumulate layers")
+	assert.Equal(t, "bytes", v.Get("bar"))
+
+	// unnamed layers are re-applied before the reloaded files
+	assert.NoError(t, v.Reload())
+	assert.Len(t, v.files.layers, 2)
+	assert.Equal(t, "file", v.Get("foo"))
+	assert.Equal(t, "file", v.Get("bar"))
+	assert.Equal(t, "env", v.Get("baz"))
+}
@@ -0,0 +1,241 @@
+package venom
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// LoadLimits describes the maximum size and shape of config data. Each limit is
+// disabled when zero.
+type LoadLimits struct {
+	// MaxFileSize is the maximum number of bytes read from a single file or
+	// io.Reader
+	MaxFileSize int64
+
+	// MaxDepth is the maximum nesting depth of maps and slices, where the top
+	// level keys of a file have a depth of 1
+	MaxDepth int
+
+	// MaxKeys is the maximum number of keys, across all nested maps, within a
+	// single file
+	MaxKeys int
+
+	// MaxStringLength is the maximum length, in bytes, of any key or string
+	// value
+	MaxStringLength int
+}
+
+// loadLimits holds the LoadLimits of a Venom instance
+type loadLimits struct {
+	mu     sync.Mutex
+	limits LoadLimits
+}
+
+// SetLimits restricts the size and shape of the config data decoded by every
+// IOFileLoader when loading files, directories, readers and byte slices, as
+// well as the environment data loaded by LoadEnvironment. The zero value
+// imposes no limits.
+//
+// Limits should be set when loading config data from untrusted sources.
+func (v *Venom) SetLimits(limits LoadLimits) {
+	v.limits.mu.Lock()
+	defer v.limits.mu.Unlock()
+	v.limits.limits = limits
+}
+
+// Limits returns the LoadLimits set via SetLimits
+func (v *Venom) Limits() LoadLimits {
+	v.limits.mu.Lock()
+	defer v.limits.mu.Unlock()
+	return v.limits.limits
+}
+
+// Names of the limits reported by a LimitError
+const (
+	LimitFileSize     = "MaxFileSize"
+	LimitDepth        = "MaxDepth"
+	LimitKeys         = "MaxKeys"
+	LimitStringLength = "MaxStringLength"
+)
+
+// A LimitError is returned, wrapped in a LoadError, when config data exceeds
+// one of the LoadLimits set via SetLimits
+type LimitError struct {
+	// Limit is the name of the exceeded limit, ie LimitDepth
+	Limit string
+
+	// Max is the configured value of the exceeded limit
+	Max int64
+
+	// Key is the key at which the limit was exceeded, if known
+	Key string
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current LimitError instance
+func (e *LimitError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("config exceeds %s of %d", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("config exceeds %s of %d at key %q", e.Limit, e.Max, e.Key)
+}
+
+// limitedReader reads from an io.Reader until more than the MaxFileSize of its
+// limits have been read, at which point it returns a LimitError. The remaining
+// limits are made available to the IOFileLoaders which enforce them while
+// decoding, via limitsOf.
+type limitedReader struct {
+	r        io.Reader
+	limits   LoadLimits
+	read     int64
+	exceeded bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	max := l.limits.MaxFileSize
+	if max <= 0 {
+		return l.r.Read(p)
+	}
+	if l.exceeded {
+		return 0, l.err()
+	}
+
+	// read up to one byte beyond the limit, so that data of exactly the
+	// maximum size is accepted
+	if remaining := max + 1 - l.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > max {
+		l.exceeded = true
+		return n - int(l.read-max), l.err()
+	}
+	return n, err
+}
+
+func (l *limitedReader) err() error {
+	return &LimitError{Limit: LimitFileSize, Max: l.limits.MaxFileSize}
+}
+
+// limitReader returns the provided io.Reader restricted to the provided
+// limits, if any
+func (l LoadLimits) limitReader(r io.Reader) (io.Reader, *limitedReader) {
+	if l == (LoadLimits{}) {
+		return r, nil
+	}
+	limited := &limitedReader{r: r, limits: l}
+	return limited, limited
+}
+
+// limitsOf returns the limits which apply to the config data read from the
+// provided io.Reader
+func limitsOf(r io.Reader) LoadLimits {
+	if limited, ok := r.(*limitedReader); ok {
+		return limited.limits
+	}
+	return LoadLimits{}
+}
+
+// shaped returns true if any of the limits on the shape of config data are set
+func (l LoadLimits) shaped() bool {
+	return l.MaxDepth > 0 || l.MaxKeys > 0 || l.MaxStringLength > 0
+}
+
+// A limitCounter enforces the limits on the shape of config data as it is
+// walked or decoded, counting the keys seen so far
+type limitCounter struct {
+	limits LoadLimits
+	keys   int
+}
+
+// nested returns a LimitError if a map or slice at key, at the provided depth,
+// exceeds the MaxDepth
+func (c *limitCounter) nested(key string, depth int) error {
+	if c.limits.MaxDepth > 0 && depth > c.limits.MaxDepth {
+		return &LimitError{Limit: LimitDepth, Max: int64(c.limits.MaxDepth), Key: key}
+	}
+	return nil
+}
+
+// key counts the named map key, at the full key, returning a LimitError if it
+// exceeds the MaxKeys or MaxStringLength
+func (c *limitCounter) key(key, name string) error {
+	c.keys++
+	if c.limits.MaxKeys > 0 && c.keys > c.limits.MaxKeys {
+		return &LimitError{Limit: LimitKeys, Max: int64(c.limits.MaxKeys), Key: key}
+	}
+	return c.str(key, name)
+}
+
+// str returns a LimitError if the provided string, at key, exceeds the
+// MaxStringLength
+func (c *limitCounter) str(key, s string) error {
+	if c.limits.MaxStringLength > 0 && len(s) > c.limits.MaxStringLength {
+		return &LimitError{Limit: LimitStringLength, Max: int64(c.limits.MaxStringLength), Key: key}
+	}
+	return nil
+}
+
+// childKey returns the full key of the named child of the provided key
+func childKey(key, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + Delim + name
+}
+
+// check returns a LimitError if the provided config data exceeds any of the
+// limits
+func (l LoadLimits) check(data map[string]interface{}) error {
+	if !l.shaped() {
+		return nil
+	}
+
+	counter := &limitCounter{limits: l}
+	var walk func(key string, val interface{}, depth int) error
+	walk = func(key string, val interface{}, depth int) error {
+		switch actual := val.(type) {
+		case string:
+			return counter.str(key, actual)
+		case map[string]interface{}, ConfigMap, map[interface{}]interface{}, []interface{}:
+		default:
+			return nil
+		}
+
+		if err := counter.nested(key, depth); err != nil {
+			return err
+		}
+
+		var children map[string]interface{}
+		switch actual := val.(type) {
+		case map[string]interface{}:
+			children = actual
+		case ConfigMap:
+			children = actual
+		case map[interface{}]interface{}:
+			children = mapInterfaceInterfaceToStrInterface(actual)
+		case []interface{}:
+			for index, item := range actual {
+				if err := walk(fmt.Sprintf("%s[%d]", key, index), item, depth+1); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		for name, item := range children {
+			child := childKey(key, name)
+			if err := counter.key(child, name); err != nil {
+				return err
+			}
+			if err := walk(child, item, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk("", data, 1)
+}
@@ -0,0 +1,189 @@
+package venom
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadLimits(t *testing.T) {
+	testIO := []struct {
+		tc     string
+		limits LoadLimits
+		format string
+		input  string
+		limit  string
+		key    string
+	}{
+		{
+			tc:     "should accept data within every limit",
+			limits: LoadLimits{MaxFileSize: 36, MaxDepth: 4, MaxKeys: 4, MaxStringLength: 5},
+			input:  `{"a": {"b": "hello", "c": [1, [2]]}}`,
+		},
+		{
+			tc:     "should reject data exceeding the file size",
+			limits: LoadLimits{MaxFileSize: 10},
+			input:  `{"foo": "bar"}`,
+			limit:  LimitFileSize,
+		},
+		{
+			tc:     "should reject maps exceeding the depth",
+			limits: LoadLimits{MaxDepth: 2},
+			input:  `{"a": {"b": {"c": 1}}}`,
+			limit:  LimitDepth,
+			key:    "a.b",
+		},
+		{
+			tc:     "should reject slices exceeding the depth",
+			limits: LoadLimits{MaxDepth: 2},
+			input:  `{"a": [[1]]}`,
+			limit:  LimitDepth,
+			key:    "a[0]",
+		},
+		{
+			tc:     "should reject data exceeding the key count",
+			limits: LoadLimits{MaxKeys: 2},
+			input:  `{"a": {"b": 1, "c": 2}}`,
+			limit:  LimitKeys,
+		},
+		{
+			tc:     "should reject string values exceeding the string length",
+			limits: LoadLimits{MaxStringLength: 3},
+			input:  `{"a": {"b": "toolong"}}`,
+			limit:  LimitStringLength,
+			key:    "a.b",
+		},
+		{
+			tc:     "should reject keys exceeding the string length",
+			limits: LoadLimits{MaxStringLength: 3},
+			input:  `{"a": {"toolong": 1}}`,
+			limit:  LimitStringLength,
+			key:    "a.toolong",
+		},
+		{
+			tc:     "should reject jsonc data exceeding the depth",
+			limits: LoadLimits{MaxDepth: 2},
+			format: "jsonc",
+			input:  `{"a": {"b": {"c": 1}}} // nested`,
+			limit:  LimitDepth,
+			key:    "a.b",
+		},
+		{
+			tc:     "should reject xml data exceeding the depth",
+			limits: LoadLimits{MaxDepth: 2},
+			format: "xml",
+			input:  `<config><a><b><c>1</c></b></a></config>`,
+			limit:  LimitDepth,
+			key:    "a.b",
+		},
+		{
+			tc:     "should reject xml data exceeding the key count",
+			limits: LoadLimits{MaxKeys: 2},
+			format: "xml",
+			input:  `<config><a x="1"><b>1</b></a></config>`,
+			limit:  LimitKeys,
+			key:    "a.b",
+		},
+		{
+			tc:     "should reject xml text exceeding the string length",
+			limits: LoadLimits{MaxStringLength: 3},
+			format: "xml",
+			input:  `<config><a>toolong</a></config>`,
+			limit:  LimitStringLength,
+			key:    "a",
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			v := New()
+			v.SetLimits(test.limits)
+			format := test.format
+			if format == "" {
+				format = "json"
+			}
+			err := v.LoadBytes([]byte(test.input), format, FileLevel)
+			if test.limit == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var limitErr *LimitError
+			if assert.True(t, errors.As(err, &limitErr), "%v", err) {
+				assert.Equal(t, test.limit, limitErr.Limit)
+				if test.key != "" {
+					assert.Equal(t, test.key, limitErr.Key)
+				}
+			}
+			var loadErr *LoadError
+			assert.True(t, errors.As(err, &loadErr))
+			assert.Equal(t, 0, v.Size())
+		})
+	}
+}
+
+func TestLoadLimitsLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json":     `{"$include": "large.json"}`,
+		"large.json":      `{"data": "` + strings.Repeat("a", 64) + `"}`,
+		"config.swallows": `{"name": "app"}`,
+	})
+
+	t.Run("should apply limits to included files", func(t *testing.T) {
+		v := New()
+		v.SetLimits(LoadLimits{MaxFileSize: 32})
+		err := v.LoadFile(filepath.Join(dir, "config.json"))
+
+		var loadErr *LoadError
+		if assert.True(t, errors.As(err, &loadErr), "%v", err) {
+			assert.Equal(t, filepath.Join(dir, "large.json"), loadErr.Path)
+		}
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, 0, v.Size())
+	})
+
+	t.Run("should report limits when loaders swallow read errors", func(t *testing.T) {
+		RegisterExtension("swallows", func(r io.Reader) (map[string]interface{}, error) {
+			_, _ = io.Copy(io.Discard, r)
+			return map[string]interface{}{"name": "app"}, nil
+		})
+		defer delete(extensionMap, "swallows")
+
+		v := New()
+		v.SetLimits(LoadLimits{MaxFileSize: 4})
+		err := v.LoadFile(filepath.Join(dir, "config.swallows"))
+
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr), "%v", err)
+		assert.Equal(t, 0, v.Size())
+	})
+}
+
+func TestLoadLimitsPerInstance(t *testing.T) {
+	limited := New()
+	limited.SetLimits(LoadLimits{MaxKeys: 1})
+	assert.Equal(t, LoadLimits{MaxKeys: 1}, limited.Limits())
+
+	input := []byte(`{"a": 1, "b": 2}`)
+	var limitErr *LimitError
+	assert.True(t, errors.As(limited.LoadBytes(input, "json", FileLevel), &limitErr))
+	assert.NoError(t, New().LoadBytes(input, "json", FileLevel))
+}
+
+func TestLoadXMLMaxDepth(t *testing.T) {
+	depth := maxXMLDepth + 1
+	input := "<config>" + strings.Repeat("<a>", depth) + strings.Repeat("</a>", depth) + "</config>"
+
+	v := New()
+	err := v.LoadBytes([]byte(input), "xml", FileLevel)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "max depth")
+	}
+	assert.Equal(t, 0, v.Size())
+}
@@ -0,0 +1,209 @@
+package venom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSnippetLen is the maximum length of the LoadError Snippet. Longer lines,
+// such as those of minified documents, are truncated around the error.
+const maxSnippetLen = 80
+
+// A LoadError is returned when config data can not be decoded by an
+// IOFileLoader. Line and Column are 1-indexed, with Column counting bytes,
+// and, along with the Snippet of the offending line, are only set if the
+// position of the error is known.
+type LoadError struct {
+	// Path is the path of the file being loaded, which is empty if the data
+	// was not loaded from a file
+	Path string
+
+	// Format is the extension of the IOFileLoader used to decode the data,
+	// ie "json"
+	Format string
+
+	Line   int
+	Column int
+	Offset int64
+
+	// Snippet is the line containing the error
+	Snippet string
+
+	Err error
+
+	// caret is the 1-indexed rune column of the error within the Snippet
+	caret int
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current LoadError instance, including the offending line if known
+func (e *LoadError) Error() string {
+	source := e.Path
+	if source == "" {
+		source = e.Format + " input"
+	}
+
+	if e.Line == 0 {
+		return fmt.Sprintf("venom: %s: %s", source, e.Err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "venom: %s: line %d, column %d: %s", source, e.Line, e.Column, e.Err)
+	if e.Snippet != "" {
+		fmt.Fprintf(&b, "\n\t%s\n\t%s^", e.Snippet, caretPadding(e.Snippet, e.snippetColumn()))
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying decoding error
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// snippetColumn returns the 1-indexed column of the error within the Snippet,
+// which differs from Column if the Snippet was truncated or contains
+// multi-byte runes. The rune column found when taking the Snippet is used if
+// known, otherwise the byte Column is used, assuming the Snippet was truncated
+// as by newLoadError.
+func (e *LoadError) snippetColumn() int {
+	if e.caret > 0 {
+		return e.caret
+	}
+	if e.Column <= maxSnippetLen/2 {
+		return e.Column
+	}
+	return maxSnippetLen/2 + 1
+}
+
+// caretPadding returns the whitespace preceding a caret pointing at the
+// 1-indexed rune column of line, retaining any tabs so that the caret is
+// aligned
+func caretPadding(line string, column int) string {
+	var b strings.Builder
+	i := 0
+	for _, r := range line {
+		if i >= column-1 {
+			break
+		}
+		if r == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+		i++
+	}
+	return b.String()
+}
+
+// newLoadError returns a LoadError for an error encountered at the provided
+// byte offset within data
+func newLoadError(format string, data []byte, offset int, err error) *LoadError {
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(data) {
+		offset = len(data)
+	}
+
+	line, column := lineColumn(data, offset)
+	snip, caret := snippet(data, offset, column)
+	return &LoadError{
+		Format:  format,
+		Line:    line,
+		Column:  column,
+		Offset:  int64(offset),
+		Snippet: snip,
+		Err:     err,
+		caret:   caret,
+	}
+}
+
+// lineColumn returns the 1-indexed line and column of the byte at the provided
+// offset within data
+func lineColumn(data []byte, offset int) (line, column int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+
+	line = 1 + bytes.Count(data[:offset], []byte("\n"))
+	column = offset - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}
+
+// snippet returns the line of data containing the byte at the provided offset,
+// truncated to at most maxSnippetLen bytes around the 1-indexed column of the
+// offset without splitting any multi-byte runes, along with the 1-indexed rune
+// column of the offset within the snippet
+func snippet(data []byte, offset, column int) (string, int) {
+	start := offset - (column - 1)
+	end := len(data)
+	if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+		end = start + i
+	}
+	line := bytes.TrimRight(data[start:end], "\r")
+
+	// errors within any trimmed carriage returns are located at the end of
+	// the line
+	index := column - 1
+	if index > len(line) {
+		index = len(line)
+	}
+
+	from := 0
+	if index > maxSnippetLen/2 {
+		from = index - maxSnippetLen/2
+	}
+	for from > 0 && !utf8.RuneStart(line[from]) {
+		from--
+	}
+	to := from + maxSnippetLen
+	if to > len(line) {
+		to = len(line)
+	}
+	for to > from && to < len(line) && !utf8.RuneStart(line[to]) {
+		to--
+	}
+
+	return string(line[from:to]), utf8.RuneCount(line[from:index]) + 1
+}
+
+// jsonLoadError returns a LoadError for an error returned by encoding/json
+// while decoding data. The offset of the error is converted by the provided
+// function, allowing errors to be reported against pre-processed documents.
+func jsonLoadError(format string, data []byte, err error, position func(offset int64) int) *LoadError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return newLoadError(format, data, position(syntaxErr.Offset), err)
+	case errors.As(err, &typeErr):
+		return newLoadError(format, data, position(typeErr.Offset), err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return newLoadError(format, data, len(data), err)
+	}
+	return &LoadError{Format: format, Err: err}
+}
+
+// asLoadError returns the provided error, returned by the IOFileLoader for the
+// provided format, as a LoadError
+func asLoadError(format string, err error) *LoadError {
+	if loadErr, ok := err.(*LoadError); ok {
+		if loadErr.Format == "" {
+			loadErr.Format = format
+		}
+		return loadErr
+	}
+	return &LoadError{Format: format, Err: err}
+}
+
+// withPath sets the Path of the provided error, if it is a LoadError
+func withPath(err error, name string) error {
+	if loadErr, ok := err.(*LoadError); ok {
+		loadErr.Path = name
+	}
+	return err
+}
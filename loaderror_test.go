@@ -0,0 +1,238 @@
+package venom
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoaderErrors(t *testing.T) {
+	testIO := []struct {
+		tc      string
+		loader  IOFileLoader
+		input   string
+		format  string
+		line    int
+		column  int
+		snippet string
+		wrapped interface{}
+	}{
+		{
+			tc:      "should locate json syntax errors",
+			loader:  JSONLoader,
+			input:   "{\n  \"foo\": \"bar\",\n  \"baz\": ]\n}",
+			format:  jsonKey,
+			line:    3,
+			column:  10,
+			snippet: `  "baz": ]`,
+			wrapped: new(*json.SyntaxError),
+		},
+		{
+			tc:      "should locate json type errors",
+			loader:  JSONLoader,
+			input:   `["foo"]`,
+			format:  jsonKey,
+			line:    1,
+			column:  1,
+			snippet: `["foo"]`,
+			wrapped: new(*json.UnmarshalTypeError),
+		},
+		{
+			tc:      "should locate truncated json documents",
+			loader:  JSONLoader,
+			input:   "{\n  \"foo\": ",
+			format:  jsonKey,
+			line:    2,
+			column:  10,
+			snippet: `  "foo": `,
+		},
+		{
+			tc:      "should locate jsonc errors within the original document",
+			loader:  JSONCLoader,
+			input:   "{\n  // comment\n  \"foo\": }",
+			format:  jsoncKey,
+			line:    3,
+			column:  10,
+			snippet: `  "foo": }`,
+			wrapped: new(*json.SyntaxError),
+		},
+		{
+			tc:      "should locate xml syntax errors",
+			loader:  XMLLoader,
+			input:   "<config>\n  <foo>bar</baz>\n</config>",
+			format:  xmlKey,
+			line:    2,
+			column:  17,
+			snippet: `  <foo>bar</baz>`,
+			wrapped: new(*xml.SyntaxError),
+		},
+		{
+			tc:      "should locate json errors after trailing carriage returns",
+			loader:  JSONLoader,
+			input:   `{"a":` + strings.Repeat("\r", 60),
+			format:  jsonKey,
+			line:    1,
+			column:  66,
+			snippet: `{"a":`,
+		},
+		{
+			tc:      "should locate jsonc errors after trailing carriage returns",
+			loader:  JSONCLoader,
+			input:   `{"a":` + strings.Repeat("\r", 60),
+			format:  jsoncKey,
+			line:    1,
+			column:  65,
+			snippet: `{"a":`,
+		},
+		{
+			tc:      "should locate xml errors after trailing carriage returns",
+			loader:  XMLLoader,
+			input:   `<config>` + strings.Repeat("\r", 60),
+			format:  xmlKey,
+			line:    1,
+			column:  69,
+			snippet: `<config>`,
+			wrapped: new(*xml.SyntaxError),
+		},
+		{
+			tc:      "should not split multi-byte runes when truncating snippets",
+			loader:  JSONLoader,
+			input:   `{"k": "` + strings.Repeat("é", 50) + `"  x}`,
+			format:  jsonKey,
+			line:    1,
+			column:  111,
+			snippet: strings.Repeat("é", 19) + `"  x}`,
+			wrapped: new(*json.SyntaxError),
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			_, err := test.loader(strings.NewReader(test.input))
+
+			var loadErr *LoadError
+			if !assert.True(t, errors.As(err, &loadErr), "%v", err) {
+				return
+			}
+			assert.Equal(t, test.format, loadErr.Format)
+			assert.Equal(t, test.line, loadErr.Line)
+			assert.Equal(t, test.column, loadErr.Column)
+			assert.Equal(t, test.snippet, loadErr.Snippet)
+			assert.Empty(t, loadErr.Path)
+			if test.wrapped != nil {
+				assert.True(t, errors.As(err, test.wrapped))
+			}
+		})
+	}
+}
+
+func TestLoadErrorMessage(t *testing.T) {
+	err := newLoadError(jsonKey, []byte("{\n\t\"foo\": ]\n}"), 10, errors.New("invalid character"))
+	err.Path = "config.json"
+	assert.Equal(t, "venom: config.json: line 2, column 9: invalid character\n\t\t\"foo\": ]\n\t\t       ^", err.Error())
+
+	err = &LoadError{Format: "yaml", Err: errors.New("bad indentation")}
+	assert.Equal(t, "venom: yaml input: bad indentation", err.Error())
+
+	t.Run("should truncate long lines around the error", func(t *testing.T) {
+		line := `{"key": "` + strings.Repeat("a", 100) + `", "bad": ], "tail": "` + strings.Repeat("b", 100) + `"}`
+		err := newLoadError(jsonKey, []byte(line), strings.Index(line, "]"), errors.New("invalid character"))
+
+		assert.Equal(t, 120, err.Column)
+		assert.Len(t, err.Snippet, maxSnippetLen)
+		assert.Equal(t, byte(']'), err.Snippet[err.snippetColumn()-1])
+	})
+
+	t.Run("should align the caret with multi-byte runes", func(t *testing.T) {
+		data := []byte(`{"ключ": ]}`)
+		err := newLoadError(jsonKey, data, bytes.IndexByte(data, ']'), errors.New("invalid character"))
+		assert.Equal(t, "venom: json input: line 1, column 14: invalid character\n\t{\"ключ\": ]}\n\t         ^", err.Error())
+	})
+}
+
+func TestLoadErrorSources(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"bad/config.json":      `{"foo": }`,
+		"include/config.json":  `{"$include": "other.json"}`,
+		"include/other.json":   "{\n  \"foo\": }",
+		"custom/config.custom": `anything`,
+	})
+
+	customErr := errors.New("custom loader failure")
+	RegisterExtension("custom", func(io.Reader) (map[string]interface{}, error) {
+		return nil, customErr
+	})
+	defer delete(extensionMap, "custom")
+
+	testIO := []struct {
+		tc     string
+		load   func(v *Venom) error
+		path   string
+		format string
+		line   int
+	}{
+		{
+			tc:     "LoadFile",
+			load:   func(v *Venom) error { return v.LoadFile(filepath.Join(dir, "bad", "config.json")) },
+			path:   filepath.Join(dir, "bad", "config.json"),
+			format: jsonKey,
+			line:   1,
+		},
+		{
+			tc: "LoadDirectory",
+			load: func(v *Venom) error {
+				return v.LoadDirectoryWith(FileLevel, filepath.Join(dir, "bad"), DirectoryOptions{Atomic: true})
+			},
+			path:   filepath.Join(dir, "bad", "config.json"),
+			format: jsonKey,
+			line:   1,
+		},
+		{
+			tc:     "LoadFile with includes",
+			load:   func(v *Venom) error { return v.LoadFile(filepath.Join(dir, "include", "config.json")) },
+			path:   filepath.Join(dir, "include", "other.json"),
+			format: jsonKey,
+			line:   2,
+		},
+		{
+			tc:     "LoadReader",
+			load:   func(v *Venom) error { return v.LoadReader(strings.NewReader(`{"foo": }`), ".JSON", FileLevel) },
+			format: jsonKey,
+			line:   1,
+		},
+		{
+			tc:     "LoadFile with a custom loader",
+			load:   func(v *Venom) error { return v.LoadFile(filepath.Join(dir, "custom", "config.custom")) },
+			path:   filepath.Join(dir, "custom", "config.custom"),
+			format: "custom",
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			v := New()
+			err := test.load(v)
+
+			var loadErr *LoadError
+			if assert.True(t, errors.As(err, &loadErr), "%v", err) {
+				assert.Equal(t, test.path, loadErr.Path)
+				assert.Equal(t, test.format, loadErr.Format)
+				assert.Equal(t, test.line, loadErr.Line)
+			}
+			assert.Equal(t, 0, v.Size())
+		})
+	}
+
+	t.Run("should unwrap errors from custom loaders", func(t *testing.T) {
+		err := New().LoadFile(filepath.Join(dir, "custom", "config.custom"))
+		assert.True(t, errors.Is(err, customErr))
+	})
+}
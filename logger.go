@@ -18,6 +18,13 @@ type Logger interface {
 	LogRead(key string, val interface{}, bl bool)
 }
 
+// A ReloadLogger is notified of the result of every attempt to reload config
+// files. A Logger used by a LoggableConfigStore may implement this interface in
+// order to log reloads.
+type ReloadLogger interface {
+	LogReload(result ReloadResult)
+}
+
 // StoreLogger is Venom's default Logger.
 type StoreLogger struct {
 	Log *log.Logger
@@ -43,3 +50,14 @@ func (sl *StoreLogger) LogRead(key string, val interface{}, bl bool) {
 	logLine := fmt.Sprintf("%s%s: reading key=%s val=%s exist=%v", time.Now().UTC().Format(TIME_FORMAT), LOG_NAME, key, val, bl)
 	sl.Log.Print(logLine)
 }
+
+// LogReload is the default logging behavior of a LoggableConfigStore when
+// config files are reloaded.
+func (sl *StoreLogger) LogReload(result ReloadResult) {
+	status := "ok"
+	if result.Err != nil {
+		status = result.Err.Error()
+	}
+	logLine := fmt.Sprintf("%s%s: reloading files=%d levels=%v status=%s", time.Now().UTC().Format(TIME_FORMAT), LOG_NAME, len(result.Files), result.Levels, status)
+	sl.Log.Print(logLine)
+}
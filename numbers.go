@@ -0,0 +1,132 @@
+package venom
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// rangeErr returns the error reported when a numeric value does not fit within
+// the type it is being coerced to
+func rangeErr(val interface{}) error {
+	return &strconv.NumError{
+		Func: "coerce",
+		Num:  fmt.Sprint(val),
+		Err:  strconv.ErrRange,
+	}
+}
+
+// signedNumber converts any integer or json.Number representation of a number
+// into an int64, ensuring that it fits within the provided bit size. A bit
+// size of 0 corresponds to int. The returned bool is false if val does not
+// hold a supported representation.
+func signedNumber(val interface{}, bitSize int) (int64, bool, error) {
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+
+	var n int64
+	switch value := val.(type) {
+	case json.Number:
+		parsed, err := strconv.ParseInt(string(value), 10, bitSize)
+		return parsed, true, err
+	case int:
+		n = int64(value)
+	case int8:
+		n = int64(value)
+	case int16:
+		n = int64(value)
+	case int32:
+		n = int64(value)
+	case int64:
+		n = value
+	default:
+		u, ok, err := unsignedNumber(val, 64)
+		if !ok || err != nil {
+			return 0, ok, err
+		}
+		if u > math.MaxInt64 {
+			return 0, true, rangeErr(val)
+		}
+		n = int64(u)
+	}
+
+	if bitSize < 64 {
+		min, max := int64(-1)<<uint(bitSize-1), int64(1)<<uint(bitSize-1)-1
+		if n < min || n > max {
+			return 0, true, rangeErr(val)
+		}
+	}
+	return n, true, nil
+}
+
+// unsignedNumber converts any integer or json.Number representation of a
+// number into a uint64, ensuring that it fits within the provided bit size. A
+// bit size of 0 corresponds to uint. The returned bool is false if val does
+// not hold a supported representation.
+func unsignedNumber(val interface{}, bitSize int) (uint64, bool, error) {
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+
+	var n uint64
+	switch value := val.(type) {
+	case json.Number:
+		parsed, err := strconv.ParseUint(string(value), 10, bitSize)
+		return parsed, true, err
+	case uint:
+		n = uint64(value)
+	case uint8:
+		n = uint64(value)
+	case uint16:
+		n = uint64(value)
+	case uint32:
+		n = uint64(value)
+	case uint64:
+		n = value
+	case int, int8, int16, int32, int64:
+		signed, _, _ := signedNumber(val, 64)
+		if signed < 0 {
+			return 0, true, rangeErr(val)
+		}
+		n = uint64(signed)
+	default:
+		return 0, false, nil
+	}
+
+	if bitSize < 64 && n > uint64(1)<<uint(bitSize)-1 {
+		return 0, true, rangeErr(val)
+	}
+	return n, true, nil
+}
+
+// floatNumber converts any float, integer or json.Number representation of a
+// number into a float64, ensuring that it fits within the provided bit size.
+// The returned bool is false if val does not hold a supported representation.
+func floatNumber(val interface{}, bitSize int) (float64, bool, error) {
+	var f float64
+	switch value := val.(type) {
+	case json.Number:
+		parsed, err := strconv.ParseFloat(string(value), bitSize)
+		return parsed, true, err
+	case float32:
+		return float64(value), true, nil
+	case float64:
+		f = value
+	case uint, uint8, uint16, uint32, uint64:
+		u, _, _ := unsignedNumber(val, 64)
+		f = float64(u)
+	default:
+		n, ok, err := signedNumber(val, 64)
+		if !ok || err != nil {
+			return 0, ok, err
+		}
+		f = float64(n)
+	}
+
+	if bitSize == 32 && !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+		return 0, true, rangeErr(val)
+	}
+	return f, true, nil
+}
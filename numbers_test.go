@@ -0,0 +1,156 @@
+package venom
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoerceNumbers(t *testing.T) {
+	testIO := []struct {
+		tc      string
+		coerce  func(interface{}) (interface{}, error)
+		input   interface{}
+		expect  interface{}
+		isRange bool
+		err     bool
+	}{
+		{
+			tc:     "should coerce json.Number to int",
+			coerce: func(v interface{}) (interface{}, error) { return coerceInt(v) },
+			input:  json.Number("42"),
+			expect: 42,
+		},
+		{
+			tc:     "should coerce int64 to int",
+			coerce: func(v interface{}) (interface{}, error) { return coerceInt(v) },
+			input:  int64(42),
+			expect: 42,
+		},
+		{
+			tc:     "should coerce large json.Number to int64 without losing precision",
+			coerce: func(v interface{}) (interface{}, error) { return coerceInt64(v) },
+			input:  json.Number("9007199254740993"),
+			expect: int64(9007199254740993),
+		},
+		{
+			tc:      "should error on int8 overflow",
+			coerce:  func(v interface{}) (interface{}, error) { return coerceInt8(v) },
+			input:   int64(128),
+			isRange: true,
+		},
+		{
+			tc:      "should error on json.Number int32 overflow",
+			coerce:  func(v interface{}) (interface{}, error) { return coerceInt32(v) },
+			input:   json.Number("2147483648"),
+			isRange: true,
+		},
+		{
+			tc:      "should error on uint64 to int64 overflow",
+			coerce:  func(v interface{}) (interface{}, error) { return coerceInt64(v) },
+			input:   uint64(math.MaxUint64),
+			isRange: true,
+		},
+		{
+			tc:     "should error on fractional json.Number to int",
+			coerce: func(v interface{}) (interface{}, error) { return coerceInt(v) },
+			input:  json.Number("1.5"),
+			err:    true,
+		},
+		{
+			tc:     "should coerce json.Number to uint64",
+			coerce: func(v interface{}) (interface{}, error) { return coerceUint64(v) },
+			input:  json.Number("18446744073709551615"),
+			expect: uint64(math.MaxUint64),
+		},
+		{
+			tc:     "should coerce int64 to uint16",
+			coerce: func(v interface{}) (interface{}, error) { return coerceUint16(v) },
+			input:  int64(65535),
+			expect: uint16(65535),
+		},
+		{
+			tc:      "should error on negative int64 to uint",
+			coerce:  func(v interface{}) (interface{}, error) { return coerceUint(v) },
+			input:   int64(-1),
+			isRange: true,
+		},
+		{
+			tc:      "should error on uint8 overflow",
+			coerce:  func(v interface{}) (interface{}, error) { return coerceUint8(v) },
+			input:   json.Number("256"),
+			isRange: true,
+		},
+		{
+			tc:     "should coerce json.Number to float64",
+			coerce: func(v interface{}) (interface{}, error) { return coerceFloat64(v) },
+			input:  json.Number("0.5"),
+			expect: 0.5,
+		},
+		{
+			tc:     "should coerce int64 to float64",
+			coerce: func(v interface{}) (interface{}, error) { return coerceFloat64(v) },
+			input:  int64(5),
+			expect: 5.0,
+		},
+		{
+			tc:     "should coerce float64 to float32",
+			coerce: func(v interface{}) (interface{}, error) { return coerceFloat32(v) },
+			input:  0.5,
+			expect: float32(0.5),
+		},
+		{
+			tc:      "should error on float32 overflow",
+			coerce:  func(v interface{}) (interface{}, error) { return coerceFloat32(v) },
+			input:   math.MaxFloat64,
+			isRange: true,
+		},
+		{
+			tc:     "should still reject float64 for int",
+			coerce: func(v interface{}) (interface{}, error) { return coerceInt(v) },
+			input:  5.0,
+			err:    true,
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			actual, err := test.coerce(test.input)
+			switch {
+			case test.isRange:
+				assert.True(t, errors.Is(err, strconv.ErrRange), "%v", err)
+			case test.err:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, test.expect, actual)
+			}
+		})
+	}
+}
+
+func TestUnmarshalPreciseJSONNumbers(t *testing.T) {
+	type config struct {
+		ID    int64   `venom:"id"`
+		Port  uint16  `venom:"port"`
+		Ratio float64 `venom:"ratio"`
+	}
+
+	for _, mode := range []JSONNumberMode{JSONNumbersAsNumber, JSONNumbersAsInt64} {
+		data, err := (&JSONDecoder{Numbers: mode}).Load(
+			strings.NewReader(`{"id": 9007199254740993, "port": 8080, "ratio": 0.5}`))
+		assert.NoError(t, err)
+
+		ven := New()
+		ven.Merge(FileLevel, data)
+
+		var c config
+		assert.NoError(t, Unmarshal(ven, &c))
+		assert.Equal(t, config{ID: 9007199254740993, Port: 8080, Ratio: 0.5}, c)
+	}
+}
@@ -0,0 +1,196 @@
+package venom
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// profileSettings holds the sources from which the active profiles of a Venom
+// instance are determined
+type profileSettings struct {
+	mu       sync.Mutex
+	profiles []string
+	explicit bool
+	key      string
+	env      string
+}
+
+// SetProfiles explicitly sets the active profiles, in increasing order of
+// precedence. Explicitly set profiles take precedence over those read from a
+// profile environment variable or key.
+func (v *Venom) SetProfiles(profiles ...string) {
+	v.profiles.mu.Lock()
+	defer v.profiles.mu.Unlock()
+	v.profiles.profiles = profiles
+	v.profiles.explicit = true
+}
+
+// SetProfileEnv sets the name of an environment variable, ie "APP_PROFILE",
+// containing a comma separated list of active profiles
+func (v *Venom) SetProfileEnv(name string) {
+	v.profiles.mu.Lock()
+	defer v.profiles.mu.Unlock()
+	v.profiles.env = name
+}
+
+// SetProfileKey sets the config key, ie "app.profile", whose value contains
+// the active profiles. The value may be either a comma separated string or a
+// slice of strings.
+func (v *Venom) SetProfileKey(key string) {
+	v.profiles.mu.Lock()
+	defer v.profiles.mu.Unlock()
+	v.profiles.key = key
+}
+
+// Profiles returns the currently active profiles, in increasing order of
+// precedence.
+//
+// When a config file such as "config.json" is loaded, any files named
+// "config.<profile>.json" in the same directory are loaded as overlays of it
+// for each active profile, with later profiles taking precedence over earlier
+// ones.
+func (v *Venom) Profiles() []string {
+	v.profiles.mu.Lock()
+	profiles, explicit := v.profiles.profiles, v.profiles.explicit
+	key, env := v.profiles.key, v.profiles.env
+	v.profiles.mu.Unlock()
+
+	if explicit {
+		return profiles
+	}
+
+	if env != "" {
+		if val := os.Getenv(env); val != "" {
+			return parseProfiles(val)
+		}
+	}
+
+	if key != "" {
+		if val, ok := v.Find(key); ok {
+			return parseProfiles(val)
+		}
+	}
+	return nil
+}
+
+// profilesConfigured returns true if any source of active profiles has been
+// configured
+func (v *Venom) profilesConfigured() bool {
+	v.profiles.mu.Lock()
+	defer v.profiles.mu.Unlock()
+	return v.profiles.explicit || v.profiles.key != "" || v.profiles.env != ""
+}
+
+// parseProfiles converts a comma separated string, or a slice of strings, into
+// a list of profile names
+func parseProfiles(val interface{}) []string {
+	var raw []string
+	switch actual := val.(type) {
+	case string:
+		raw = strings.Split(actual, ",")
+	case []string:
+		raw = actual
+	case []interface{}:
+		for _, item := range actual {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+
+	var profiles []string
+	for _, profile := range raw {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// A fileLoad describes a single config file to be loaded. Profile overlays
+// share the base of the file they are an overlay for.
+type fileLoad struct {
+	name    string
+	base    string
+	profile string
+	rank    int
+}
+
+// profilePath returns the path of the overlay for the provided profile of the
+// named file, ie "config.prod.json" for "config.json", or "config.prod.json.gz"
+// for "config.json.gz"
+func profilePath(name, profile string) string {
+	ext := configExt(name)
+	return strings.TrimSuffix(name, ext) + "." + profile + ext
+}
+
+// configExt returns the extension of the named file, including the extensions
+// of any IOFileDecoders, ie ".json.gz" for "config.json.gz"
+func configExt(name string) string {
+	stem := name
+	for {
+		ext := filepath.Ext(stem)
+		stem = strings.TrimSuffix(stem, ext)
+		if _, ok := encodingMap[normalizeExtension(ext)]; !ok || ext == "" {
+			return name[len(stem):]
+		}
+	}
+}
+
+// withProfiles returns the provided files, each followed by any existing
+// overlay files for the active profiles.
+//
+// Files which are themselves overlays of another of the provided files, ie
+// "config.staging.json" alongside "config.json", are omitted as they are only
+// loaded when their profile is active.
+func (v *Venom) withProfiles(files []string) []fileLoad {
+	if !v.profilesConfigured() {
+		loads := make([]fileLoad, 0, len(files))
+		for _, file := range files {
+			loads = append(loads, fileLoad{name: file, base: file})
+		}
+		return loads
+	}
+
+	bases := make(map[string]bool, len(files))
+	for _, file := range files {
+		bases[file] = true
+	}
+
+	profiles := v.Profiles()
+	loads := make([]fileLoad, 0, len(files))
+	for _, file := range files {
+		if isOverlay(file, bases) {
+			continue
+		}
+
+		loads = append(loads, fileLoad{name: file, base: file})
+		for index, profile := range profiles {
+			overlay := profilePath(file, profile)
+			if info, err := os.Stat(overlay); err != nil || info.IsDir() {
+				continue
+			}
+			loads = append(loads, fileLoad{
+				name:    overlay,
+				base:    file,
+				profile: profile,
+				rank:    index + 1,
+			})
+		}
+	}
+	return loads
+}
+
+// isOverlay returns true if the named file, ie "config.prod.json", is an
+// overlay of one of the provided base files, ie "config.json"
+func isOverlay(name string, bases map[string]bool) bool {
+	ext := configExt(name)
+	stem := strings.TrimSuffix(name, ext)
+	profileExt := filepath.Ext(stem)
+	if profileExt == "" || profileExt == stem {
+		return false
+	}
+	return bases[strings.TrimSuffix(stem, profileExt)+ext]
+}
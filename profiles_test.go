@@ -0,0 +1,142 @@
+package venom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProfiles(t *testing.T) {
+	testIO := []struct {
+		tc     string
+		input  interface{}
+		expect []string
+	}{
+		{"should split comma separated strings", "prod, eu,,", []string{"prod", "eu"}},
+		{"should accept string slices", []string{"prod", "eu"}, []string{"prod", "eu"}},
+		{"should accept interface slices", []interface{}{"prod", 12, "eu"}, []string{"prod", "eu"}},
+		{"should ignore unsupported types", 12, nil},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			assert.Equal(t, test.expect, parseProfiles(test.input))
+		})
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	os.Setenv("VENOM_TEST_PROFILE", "prod,eu")
+	defer os.Unsetenv("VENOM_TEST_PROFILE")
+
+	t.Run("should have no profiles by default", func(t *testing.T) {
+		assert.Nil(t, New().Profiles())
+	})
+
+	t.Run("should read profiles from a key", func(t *testing.T) {
+		v := New()
+		v.SetProfileKey("app.profile")
+		v.SetDefault("app.profile", "staging")
+		assert.Equal(t, []string{"staging"}, v.Profiles())
+	})
+
+	t.Run("should prefer the environment variable to the key", func(t *testing.T) {
+		v := New()
+		v.SetProfileKey("app.profile")
+		v.SetDefault("app.profile", "staging")
+		v.SetProfileEnv("VENOM_TEST_PROFILE")
+		assert.Equal(t, []string{"prod", "eu"}, v.Profiles())
+	})
+
+	t.Run("should prefer explicit profiles", func(t *testing.T) {
+		v := New()
+		v.SetProfileEnv("VENOM_TEST_PROFILE")
+		v.SetProfiles("dev")
+		assert.Equal(t, []string{"dev"}, v.Profiles())
+	})
+}
+
+func TestLoadProfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json":         `{"foo": "base", "region": "none", "log": "stdout", "db": {"host": "localhost"}}`,
+		"config.prod.json":    `{"foo": "prod", "log": {"level": "warn"}, "db": {"host": "prod.db"}}`,
+		"config.eu.json":      `{"region": "eu", "db": {"host": "eu.db"}}`,
+		"config.staging.json": `{"foo": "staging"}`,
+		"more.config.json":    `{"more": true}`,
+	})
+
+	t.Run("should ignore profile overlays when no profiles are configured", func(t *testing.T) {
+		v := New()
+		assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+		assert.Equal(t, "base", v.Get("foo"))
+	})
+
+	t.Run("should layer active profiles above the base file", func(t *testing.T) {
+		v := New()
+		v.SetProfiles("prod", "eu")
+		assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+
+		assert.Equal(t, "prod", v.Get("foo"))
+		assert.Equal(t, "eu", v.Get("region"))
+		assert.Equal(t, "eu.db", v.Get("db.host"))
+		assert.Equal(t, "warn", v.Get("log.level"))
+	})
+
+	t.Run("should only load overlays of active profiles from a directory", func(t *testing.T) {
+		v := New()
+		v.SetProfiles("prod")
+		assert.NoError(t, v.LoadDirectory(dir, false))
+
+		assert.Equal(t, "prod", v.Get("foo"))
+		assert.Equal(t, "none", v.Get("region"))
+		assert.Equal(t, true, v.Get("more"))
+	})
+
+	t.Run("should load overlays atomically", func(t *testing.T) {
+		v := New()
+		v.SetProfiles("eu", "prod")
+		assert.NoError(t, v.LoadDirectoryWith(FileLevel, dir, DirectoryOptions{Atomic: true}))
+
+		assert.Equal(t, "prod", v.Get("foo"))
+		assert.Equal(t, "prod.db", v.Get("db.host"))
+	})
+
+	t.Run("should explain which profile file won", func(t *testing.T) {
+		v := New()
+		v.SetProfiles("prod", "eu")
+		assert.NoError(t, v.LoadDirectory(dir, false))
+
+		e := v.Explain("db.host")
+		assert.Equal(t, filepath.Join(dir, "config.eu.json"), e.File)
+		assert.Equal(t, "eu", e.Profile)
+
+		e = v.Explain("foo")
+		assert.Equal(t, filepath.Join(dir, "config.prod.json"), e.File)
+		assert.Equal(t, "prod", e.Profile)
+
+		e = v.Explain("more")
+		assert.Equal(t, filepath.Join(dir, "more.config.json"), e.File)
+		assert.Equal(t, "", e.Profile)
+	})
+
+	t.Run("should load overlays of compressed files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"config.json.gz":      string(gzipBytes(t, `{"foo": "base"}`)),
+			"config.prod.json.gz": string(gzipBytes(t, `{"foo": "prod"}`)),
+		})
+
+		v := New()
+		v.SetProfiles("prod")
+		assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json.gz")))
+		assert.Equal(t, "prod", v.Get("foo"))
+
+		v = New()
+		v.SetProfiles("staging")
+		assert.NoError(t, v.LoadDirectory(dir, false))
+		assert.Equal(t, "base", v.Get("foo"))
+	})
+}
@@ -0,0 +1,266 @@
+package venom
+
+import (
+	"reflect"
+	"sort"
+)
+
+// A configSource is a file, or directory of files, which has been loaded into a
+// Venom instance and may be re-read in order to reload its config data
+type configSource interface {
+	// level returns the ConfigLevel that the source is loaded into
+	level() ConfigLevel
+
+	// read reads the current config data of the source, returning a fileLayer
+	// for each file in increasing order of precedence. If an error is
+	// returned, any layers read before the error was encountered are returned
+	// alongside it.
+	read(v *Venom) ([]*fileLayer, error)
+
+	// paths returns the paths of the files which currently make up the
+	// source, including any that do not yet exist
+	paths(v *Venom) []string
+}
+
+// A fileSource is a single config file, along with its profile overlays
+type fileSource struct {
+	name string
+	at   ConfigLevel
+}
+
+func (s *fileSource) level() ConfigLevel {
+	return s.at
+}
+
+func (s *fileSource) read(v *Venom) ([]*fileLayer, error) {
+	loads := v.withProfiles([]string{s.name})
+	layers := make([]*fileLayer, 0, len(loads))
+	for _, load := range loads {
+		loaded, err := v.readLayers(s.at, load)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, loaded...)
+	}
+	return layers, nil
+}
+
+func (s *fileSource) paths(v *Venom) []string {
+	return loadNames(v.withProfiles([]string{s.name}))
+}
+
+// A directorySource is a directory of config files
+type directorySource struct {
+	dir  string
+	at   ConfigLevel
+	opts DirectoryOptions
+}
+
+func (s *directorySource) level() ConfigLevel {
+	return s.at
+}
+
+func (s *directorySource) read(v *Venom) ([]*fileLayer, error) {
+	configFiles, errs := findFiles(s.dir, s.opts)
+	loads := v.withProfiles(configFiles)
+	if !s.opts.Atomic {
+		if len(errs) > 0 {
+			return nil, sortedErrors(errs)[0]
+		}
+
+		var layers []*fileLayer
+		for _, load := range loads {
+			loaded, err := v.readLayers(s.at, load)
+			if err != nil {
+				return layers, err
+			}
+			layers = append(layers, loaded...)
+		}
+		return layers, nil
+	}
+
+	layers := make([]*fileLayer, 0, len(loads))
+	for _, load := range loads {
+		loaded, err := v.readLayers(s.at, load)
+		if err != nil {
+			errs[load.name] = err
+			continue
+		}
+		layers = append(layers, loaded...)
+	}
+
+	if len(errs) > 0 {
+		return nil, &DirectoryError{Dir: s.dir, Errors: errs}
+	}
+	return layers, nil
+}
+
+func (s *directorySource) paths(v *Venom) []string {
+	configFiles, _ := findFiles(s.dir, s.opts)
+	return loadNames(v.withProfiles(configFiles))
+}
+
+// loadNames returns the names of the provided files
+func loadNames(loads []fileLoad) []string {
+	names := make([]string, 0, len(loads))
+	for _, load := range loads {
+		names = append(names, load.name)
+	}
+	return names
+}
+
+// loadSource reads the provided configSource, merging each of its files into
+// the configs. Once successfully loaded, the source is re-read by Reload.
+func (v *Venom) loadSource(src configSource) error {
+	layers, err := src.read(v)
+	for _, layer := range layers {
+		layer.source = src
+		v.mergeLayer(layer)
+	}
+	if err != nil {
+		return err
+	}
+
+	v.files.mu.Lock()
+	defer v.files.mu.Unlock()
+	for _, existing := range v.files.sources {
+		if reflect.DeepEqual(existing, src) {
+			return nil
+		}
+	}
+	v.files.sources = append(v.files.sources, src)
+	return nil
+}
+
+// ReloadEventKey is the Key of the Event emitted by a SubscriptionStore for the
+// result of each reload. The Value of the Event is a ReloadResult.
+const ReloadEventKey = "$reload"
+
+// A ReloadResult describes the outcome of an attempt to reload config files
+type ReloadResult struct {
+	// Files lists the config files which were read, in the order they were
+	// read
+	Files []string
+
+	// Levels lists the ConfigLevels which were rebuilt from the reloaded
+	// files. Levels is empty if the reload failed.
+	Levels []ConfigLevel
+
+	// Err is the error which caused the reload to fail, if any
+	Err error
+}
+
+// Reload re-reads every file and directory loaded via LoadFile, LoadDirectory,
+// or any of their variants, in the order in which they were first loaded. Each
+// ConfigLevel that files were loaded into is then rebuilt from the reloaded
+// files, along with any data loaded via LoadReader, LoadBytes or LoadFS,
+// meaning that keys removed from a file are removed from the configs. Values
+// set directly into those levels, ie via SetLevel, are discarded.
+//
+// The rebuilt levels are first applied to a staging copy of the ConfigStore,
+// which every registered Validator is run against. The current configs are
+// only replaced once every file has been parsed and every Validator has
+// passed, otherwise the error is returned and the configs are left untouched.
+//
+// The result of every reload is reported to the ConfigStore if it implements
+// ReloadLogger, as both LoggableConfigStore and SubscriptionStore do.
+func (v *Venom) Reload() error {
+	return v.reloadAndReport().Err
+}
+
+// reloadAndReport reloads the configs, reporting the result to the ConfigStore
+// if it implements ReloadLogger
+func (v *Venom) reloadAndReport() ReloadResult {
+	result, notify := v.reload()
+	notify()
+	if logger, ok := v.Store.(ReloadLogger); ok {
+		logger.LogReload(result)
+	}
+	return result
+}
+
+// reload rebuilds the configs from every loaded source, returning the result
+// along with a function which sends any notifications of the changes made to
+// the ConfigStore. The notifications are deferred so that they are not sent
+// while loaded files are locked, where a subscriber which is not receiving
+// events would otherwise block every other load.
+func (v *Venom) reload() (ReloadResult, func()) {
+	v.files.update.Lock()
+	defer v.files.update.Unlock()
+
+	var result ReloadResult
+	layers, levels, err := v.files.readSources(v)
+	for _, layer := range layers {
+		if layer.source != nil {
+			result.Files = append(result.Files, layer.name)
+		}
+	}
+	if err != nil {
+		result.Err = err
+		return result, func() {}
+	}
+
+	data := make(map[ConfigLevel]ConfigMap, len(levels))
+	staging := copyStore(v.Store)
+	for _, level := range levels {
+		data[level] = levelData(layers, level)
+		replaceLevel(staging, level, data[level])
+	}
+
+	if err := v.validate(staging); err != nil {
+		result.Err = err
+		return result, func() {}
+	}
+
+	v.files.mu.Lock()
+	v.files.layers = layers
+	v.files.mu.Unlock()
+
+	notifications := make([]func(), 0, len(levels))
+	for _, level := range levels {
+		notifications = append(notifications, replaceLevelDeferred(v.Store, level, data[level]))
+	}
+	result.Levels = levels
+	return result, func() {
+		for _, notify := range notifications {
+			notify()
+		}
+	}
+}
+
+// readSources re-reads every loaded source, returning the layers that would
+// result from reloading them, along with the ordered ConfigLevels that they
+// are loaded into. If any source fails to load, the layers read so far are
+// returned alongside the error.
+func (f *fileLayers) readSources(v *Venom) ([]*fileLayer, []ConfigLevel, error) {
+	f.mu.Lock()
+	sources := append([]configSource(nil), f.sources...)
+	var layers []*fileLayer
+	for _, layer := range f.layers {
+		if layer.source == nil {
+			layers = append(layers, layer)
+		}
+	}
+	f.mu.Unlock()
+
+	seen := make(map[ConfigLevel]bool)
+	var levels []ConfigLevel
+	for _, src := range sources {
+		if !seen[src.level()] {
+			seen[src.level()] = true
+			levels = append(levels, src.level())
+		}
+
+		loaded, err := src.read(v)
+		for _, layer := range loaded {
+			layer.source = src
+			layers = appendLayer(layers, layer)
+		}
+		if err != nil {
+			return layers, nil, err
+		}
+	}
+
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	return layers, levels, nil
+}
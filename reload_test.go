@@ -0,0 +1,141 @@
+package venom
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json":      `{"name": "app", "db": {"host": "localhost", "user": "admin"}}`,
+		"conf.d/10-a.json": `{"db": {"port": 5432}}`,
+		"conf.d/90-b.json": `{"db": {"host": "b.db"}}`,
+	})
+
+	v := New()
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+	assert.NoError(t, v.LoadDirectory(filepath.Join(dir, "conf.d"), false))
+	assert.NoError(t, v.LoadBytes([]byte(`{"region": "eu"}`), "json", FileLevel))
+	v.SetDefault("timeout", 10)
+
+	writeFiles(t, dir, map[string]string{
+		"config.json":      `{"name": "app", "db": {"host": "localhost"}}`,
+		"conf.d/50-c.json": `{"db": {"port": 6543}}`,
+	})
+	assert.NoError(t, v.Reload())
+
+	assert.Equal(t, "app", v.Get("name"))
+	assert.Nil(t, v.Get("db.user"))
+	assert.Equal(t, "b.db", v.Get("db.host"))
+	assert.Equal(t, 6543.0, v.Get("db.port"))
+	assert.Equal(t, "eu", v.Get("region"))
+	assert.Equal(t, 10, v.Get("timeout"))
+	assert.Equal(t, filepath.Join(dir, "conf.d", "50-c.json"), v.Explain("db.port").File)
+
+	t.Run("should leave the configs untouched on failure", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"config.json":      `{"name": "changed"}`,
+			"conf.d/60-d.json": `{"db": `,
+		})
+
+		assert.Error(t, v.Reload())
+		assert.Equal(t, "app", v.Get("name"))
+		assert.Equal(t, 6543.0, v.Get("db.port"))
+	})
+
+	t.Run("should forget loaded files when cleared", func(t *testing.T) {
+		v.Clear()
+		assert.NoError(t, v.Reload())
+		assert.Nil(t, v.Get("name"))
+	})
+}
+
+func TestReloadFailedLoads(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"name": `,
+	})
+
+	v := New()
+	assert.Error(t, v.LoadFile(filepath.Join(dir, "config.json")))
+
+	// files which failed to load are not reloaded
+	assert.NoError(t, v.Reload())
+	assert.Nil(t, v.Get("name"))
+}
+
+func TestReloadMinimalStore(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"name": "app", "db": {"host": "localhost", "user": "admin"}}`,
+	})
+
+	v := NewWithStore(minimalStore{NewDefaultConfigStore()})
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+	assert.Nil(t, v.Level(FileLevel))
+
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"name": "app", "db": {"host": "example.com"}}`,
+	})
+	assert.NoError(t, v.Reload())
+
+	// reloaded files are merged into stores which are not LevelStores
+	assert.Equal(t, "example.com", v.Get("db.host"))
+	assert.Equal(t, "admin", v.Get("db.user"))
+}
+
+func TestReloadNotifiesAfterUnlocking(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"name": "app"}`,
+		"other.json":  `{"other": true}`,
+	})
+
+	store, clear := NewSubscriptionStore(NewDefaultConfigStore())
+	defer clear()
+	events := store.Subscribe("name")
+
+	v := NewWithStore(store)
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+
+	// validators run once the reloaded files have been read, signalling that
+	// the reload is about to notify subscribers
+	validated := make(chan struct{}, 1)
+	v.RegisterValidator(func(*Venom) error {
+		select {
+		case validated <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"name": "changed"}`,
+	})
+	reloaded := make(chan error, 1)
+	go func() { reloaded <- v.Reload() }()
+	select {
+	case <-validated:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+
+	// the reload blocks until its event is received, which must not block
+	// loading other files
+	loaded := make(chan error, 1)
+	go func() { loaded <- v.LoadFile(filepath.Join(dir, "other.json")) }()
+	select {
+	case err := <-loaded:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("loading a file was blocked by a reload")
+	}
+
+	assert.Equal(t, Event{Key: "name", Value: "changed"}, <-events)
+	assert.NoError(t, <-reloaded)
+	assert.Equal(t, true, v.Get("other"))
+}
@@ -8,6 +8,26 @@ type Resolver interface {
 	Resolve([]string, ConfigMap) (val interface{}, ok bool)
 }
 
+// An ErrorResolver is a Resolver which is also capable of reporting errors
+// encountered while resolving a config, such as a file which can not be read.
+//
+// Errors are reported by Lookup and Explain, which stop searching at the first
+// error, while Find and the Get methods treat the config as not being found.
+type ErrorResolver interface {
+	Resolver
+	ResolveErr([]string, ConfigMap) (val interface{}, ok bool, err error)
+}
+
+// resolveErr resolves the specified keys using the provided Resolver,
+// reporting any error if it is an ErrorResolver
+func resolveErr(r Resolver, keys []string, config ConfigMap) (interface{}, bool, error) {
+	if er, ok := r.(ErrorResolver); ok {
+		return er.ResolveErr(keys, config)
+	}
+	val, ok := r.Resolve(keys, config)
+	return val, ok, nil
+}
+
 // DefaultResolver is the default resolver function used to resolve
 // configuration values for a level which does not specify a custom resolver.
 type DefaultResolver struct{}
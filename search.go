@@ -0,0 +1,148 @@
+package venom
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrConfigNotFound is the error returned when no config file matching the
+// configured name can be found within any of the config search paths
+type ErrConfigNotFound struct {
+	Name  string
+	Paths []string
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current ErrConfigNotFound instance
+func (e ErrConfigNotFound) Error() string {
+	return fmt.Sprintf("venom: no config file named %q found in %s", e.Name, strings.Join(e.Paths, ", "))
+}
+
+// configSearch holds the configuration used to discover config files within a
+// set of search paths
+type configSearch struct {
+	mu    sync.Mutex
+	name  string
+	paths []string
+	used  []string
+}
+
+// SetConfigName sets the name, without an extension, of the config file to
+// search for when calling ReadInConfig
+func (v *Venom) SetConfigName(name string) {
+	v.search.mu.Lock()
+	defer v.search.mu.Unlock()
+	v.search.name = name
+}
+
+// AddConfigPath appends the provided directories to the list of paths which
+// are searched, in order, when calling ReadInConfig.
+//
+// Environment variables within paths are expanded, ie "$XDG_CONFIG_HOME/app".
+// Paths which reference unset or empty environment variables are skipped.
+func (v *Venom) AddConfigPath(paths ...string) {
+	v.search.mu.Lock()
+	defer v.search.mu.Unlock()
+	v.search.paths = append(v.search.paths, paths...)
+}
+
+// ReadInConfig searches the config paths, in the order they were added, for a
+// file with the configured config name and any extension which has a
+// registered IOFileLoader. The first matching file is loaded into the
+// FileLevel, and may be retrieved via ConfigFilesUsed.
+//
+// If no matching file is found, an ErrConfigNotFound error is returned.
+func (v *Venom) ReadInConfig() error {
+	return v.readInConfig(false)
+}
+
+// ReadInAllConfigs searches the config paths in the same manner as
+// ReadInConfig, but loads every matching file rather than only the first.
+// Files found in earlier paths take precedence over files found in later
+// paths.
+func (v *Venom) ReadInAllConfigs() error {
+	return v.readInConfig(true)
+}
+
+// ConfigFilesUsed returns the paths of the config files loaded by the most
+// recent call to ReadInConfig or ReadInAllConfigs, in order of precedence
+func (v *Venom) ConfigFilesUsed() []string {
+	v.search.mu.Lock()
+	defer v.search.mu.Unlock()
+	return append([]string(nil), v.search.used...)
+}
+
+func (v *Venom) readInConfig(all bool) error {
+	v.search.mu.Lock()
+	name, paths := v.search.name, v.search.expandedPaths()
+	v.search.mu.Unlock()
+
+	found := findConfigs(name, paths, all)
+	if len(found) == 0 {
+		return ErrConfigNotFound{Name: name, Paths: paths}
+	}
+
+	// load files in reverse order of precedence, so that files found in
+	// earlier search paths are applied last
+	for i := len(found) - 1; i >= 0; i-- {
+		if err := v.LoadFile(found[i]); err != nil {
+			return err
+		}
+	}
+
+	v.search.mu.Lock()
+	defer v.search.mu.Unlock()
+	v.search.used = found
+	return nil
+}
+
+// expandedPaths returns the search paths with any environment variables
+// expanded, omitting paths which reference unset or empty variables
+func (s *configSearch) expandedPaths() []string {
+	paths := make([]string, 0, len(s.paths))
+	for _, path := range s.paths {
+		missing := false
+		expanded := os.Expand(path, func(key string) string {
+			val := os.Getenv(key)
+			if val == "" {
+				missing = true
+			}
+			return val
+		})
+		if !missing {
+			paths = append(paths, expanded)
+		}
+	}
+	return paths
+}
+
+// findConfigs returns the paths of any regular files named name, with a
+// registered extension, within the provided directories. If all is false, only
+// the first match is returned.
+func findConfigs(name string, dirs []string, all bool) []string {
+	extensions := make([]string, 0, len(extensionMap))
+	for ext := range extensionMap {
+		extensions = append(extensions, ext)
+	}
+	sort.Strings(extensions)
+
+	var found []string
+	for _, dir := range dirs {
+		for _, ext := range extensions {
+			file := filepath.Join(dir, name+"."+ext)
+			if info, err := os.Stat(file); err != nil || info.IsDir() {
+				continue
+			}
+
+			found = append(found, file)
+			if !all {
+				return found
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,68 @@
+package venom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadInConfig(t *testing.T) {
+	base := t.TempDir()
+	writeFiles(t, base, map[string]string{
+		"local/other.json": `{"foo": "other"}`,
+		"xdg/app/app.json": `{"foo": "xdg", "xdg": true}`,
+		"etc/app/app.xml":  `<Config><foo>etc</foo><etc>true</etc></Config>`,
+		"etc/app/app.json": `{"foo": "etc-json"}`,
+	})
+	os.Setenv("VENOM_TEST_XDG_CONFIG_HOME", filepath.Join(base, "xdg"))
+	defer os.Unsetenv("VENOM_TEST_XDG_CONFIG_HOME")
+
+	paths := []string{
+		filepath.Join(base, "local"),
+		"$VENOM_TEST_UNSET_DIR/app",
+		"$VENOM_TEST_XDG_CONFIG_HOME/app",
+		filepath.Join(base, "etc", "app"),
+	}
+
+	t.Run("should load the first match", func(t *testing.T) {
+		v := New()
+		v.SetConfigName("app")
+		v.AddConfigPath(paths...)
+
+		assert.NoError(t, v.ReadInConfig())
+		assert.Equal(t, []string{filepath.Join(base, "xdg", "app", "app.json")}, v.ConfigFilesUsed())
+		assert.Equal(t, "xdg", v.Get("foo"))
+		assert.Nil(t, v.Get("etc"))
+	})
+
+	t.Run("should load all matches", func(t *testing.T) {
+		v := New()
+		v.SetConfigName("app")
+		v.AddConfigPath(paths...)
+
+		assert.NoError(t, v.ReadInAllConfigs())
+		assert.Equal(t, []string{
+			filepath.Join(base, "xdg", "app", "app.json"),
+			filepath.Join(base, "etc", "app", "app.json"),
+			filepath.Join(base, "etc", "app", "app.xml"),
+		}, v.ConfigFilesUsed())
+		assert.Equal(t, "xdg", v.Get("foo"))
+		assert.Equal(t, true, v.Get("xdg"))
+		assert.Equal(t, "true", v.Get("etc"))
+	})
+
+	t.Run("should error when no config is found", func(t *testing.T) {
+		v := New()
+		v.SetConfigName("missing")
+		v.AddConfigPath(filepath.Join(base, "local"), "$VENOM_TEST_UNSET_DIR/app")
+
+		err := v.ReadInConfig()
+		assert.Equal(t, ErrConfigNotFound{
+			Name:  "missing",
+			Paths: []string{filepath.Join(base, "local")},
+		}, err)
+		assert.Empty(t, v.ConfigFilesUsed())
+	})
+}
@@ -0,0 +1,180 @@
+package venom
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSecretsDir is the directory that Docker mounts secrets into, which is
+// read by a SecretsDirectoryResolver when no Dir is configured and the
+// CredentialsDirectoryEnv environment variable is not set
+const DefaultSecretsDir = "/run/secrets"
+
+// CredentialsDirectoryEnv is the environment variable which systemd sets to the
+// directory containing the credentials of a service
+const CredentialsDirectoryEnv = "CREDENTIALS_DIRECTORY"
+
+// DefaultMaxSecretSize is the size, in bytes, of the largest secret file read
+// by a SecretsDirectoryResolver which is not configured with a MaxSize
+const DefaultMaxSecretSize = 64 << 10
+
+// SecretsSeparator is used as the delimiter for separating keys prior to
+// looking them up in a secrets directory by a SecretsDirectoryResolver which is
+// not configured with a Separator.
+//
+// ie, a SecretsSeparator of "_" will result in a lookup for "db.password"
+// reading a file named "db_password".
+var SecretsSeparator = "_"
+
+// A SecretsDirectoryResolver is a resolver capable of loading configs from a
+// directory containing one file per secret, such as the "/run/secrets"
+// directory of a Docker container or the "$CREDENTIALS_DIRECTORY" of a systemd
+// service. The contents of each file, without any trailing newlines, is
+// resolved as a string value.
+//
+// Secrets are cached after they are first read, and are re-read when the
+// modification time or size of their file changes. Files larger than MaxSize
+// are never resolved.
+type SecretsDirectoryResolver struct {
+	// Dir is the directory containing the secret files. If empty, the
+	// directory named by CredentialsDirectoryEnv is used if it is set,
+	// otherwise DefaultSecretsDir is used.
+	Dir string
+
+	// Prefix is prepended to every key before it is mapped to a file name
+	Prefix string
+
+	// Separator is used to join nested keys into a file name. If empty,
+	// SecretsSeparator is used.
+	Separator string
+
+	// Translator translates each character of the file name. If nil, file
+	// names are not translated.
+	Translator KeyTranslator
+
+	// MaxSize is the size, in bytes, of the largest secret file which is
+	// resolved. If zero, DefaultMaxSecretSize is used.
+	MaxSize int64
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// A cachedSecret is the value of a secret file, along with the modification
+// time and size of the file when it was read
+type cachedSecret struct {
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+// Resolve is a Resolver implementation which attempts to load the requested
+// configuration from a file in the secrets directory
+func (r *SecretsDirectoryResolver) Resolve(keys []string, _ ConfigMap) (val interface{}, ok bool) {
+	name := r.fileName(keys)
+	if name == "" {
+		return nil, false
+	}
+
+	path := filepath.Join(r.dir(), name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > r.maxSize() {
+		return nil, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.cache[path]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.value, true
+	}
+
+	value, err := readSecretFile(path, r.maxSize())
+	if err != nil {
+		return nil, false
+	}
+
+	if r.cache == nil {
+		r.cache = make(map[string]cachedSecret)
+	}
+	r.cache[path] = cachedSecret{modTime: info.ModTime(), size: info.Size(), value: value}
+	return value, true
+}
+
+// Invalidate discards every cached secret, causing them to be re-read when
+// they are next resolved
+func (r *SecretsDirectoryResolver) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = nil
+}
+
+// fileName maps the provided keys to the name of a secret file, returning an
+// empty string if the keys do not map to a file within the secrets directory
+func (r *SecretsDirectoryResolver) fileName(keys []string) string {
+	if len(r.Prefix) > 0 {
+		keys = append([]string{r.Prefix}, keys...)
+	}
+
+	separator := r.Separator
+	if separator == "" {
+		separator = SecretsSeparator
+	}
+
+	translator := r.Translator
+	if translator == nil {
+		translator = NoOpKeyTranslator
+	}
+
+	name := []byte(strings.Join(keys, separator))
+	for index, char := range name {
+		name[index] = translator(char)
+	}
+
+	// never resolve files outside of the secrets directory
+	if len(name) == 0 || string(name) == "." || string(name) == ".." || strings.ContainsAny(string(name), `/\`) {
+		return ""
+	}
+	return string(name)
+}
+
+// readSecretFile reads the secret file at the provided path, removing any
+// trailing newlines. A LimitError is returned if the file is larger than max
+// bytes.
+func readSecretFile(path string, max int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(file, max+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > max {
+		return "", &LimitError{Limit: LimitFileSize, Max: max}
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func (r *SecretsDirectoryResolver) dir() string {
+	if r.Dir != "" {
+		return r.Dir
+	}
+	if dir, ok := os.LookupEnv(CredentialsDirectoryEnv); ok && dir != "" {
+		return dir
+	}
+	return DefaultSecretsDir
+}
+
+func (r *SecretsDirectoryResolver) maxSize() int64 {
+	if r.MaxSize <= 0 {
+		return DefaultMaxSecretSize
+	}
+	return r.MaxSize
+}
@@ -0,0 +1,133 @@
+package venom
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretsDirectoryResolver(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"db_password":      "hunter2\n",
+		"db.password":      "dotted\n",
+		"APP_API_KEY":      "abc123",
+		"large":            strings.Repeat("x", 16),
+		"nested/db_secret": "hidden",
+	})
+
+	testIO := []struct {
+		tc       string
+		resolver *SecretsDirectoryResolver
+		key      string
+		expect   interface{}
+		expectOK bool
+	}{
+		{
+			tc:       "should resolve nested keys joined by the separator",
+			resolver: &SecretsDirectoryResolver{Dir: dir},
+			key:      "db.password",
+			expect:   "hunter2",
+			expectOK: true,
+		},
+		{
+			tc:       "should use a custom separator",
+			resolver: &SecretsDirectoryResolver{Dir: dir, Separator: "."},
+			key:      "db.password",
+			expect:   "dotted",
+			expectOK: true,
+		},
+		{
+			tc: "should use the prefix and translator",
+			resolver: &SecretsDirectoryResolver{
+				Dir:        dir,
+				Prefix:     "app",
+				Translator: DefaultEnvironmentVariableKeyTranslator,
+			},
+			key:      "api.key",
+			expect:   "abc123",
+			expectOK: true,
+		},
+		{
+			tc:       "should not resolve missing files",
+			resolver: &SecretsDirectoryResolver{Dir: dir},
+			key:      "db.user",
+		},
+		{
+			tc:       "should not resolve files larger than the max size",
+			resolver: &SecretsDirectoryResolver{Dir: dir, MaxSize: 8},
+			key:      "large",
+		},
+		{
+			tc:       "should not resolve directories",
+			resolver: &SecretsDirectoryResolver{Dir: dir},
+			key:      "nested",
+		},
+		{
+			tc:       "should not resolve files outside of the directory",
+			resolver: &SecretsDirectoryResolver{Dir: dir, Separator: "/"},
+			key:      "nested.db_secret",
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			v := New()
+			v.RegisterResolver(EnvironmentLevel+1, test.resolver)
+
+			actual, ok := v.Find(test.key)
+			assert.Equal(t, test.expectOK, ok)
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
+
+func TestSecretsDirectoryResolverDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"token": "systemd\n"})
+
+	t.Setenv(CredentialsDirectoryEnv, dir)
+	r := &SecretsDirectoryResolver{}
+	val, ok := r.Resolve([]string{"token"}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "systemd", val)
+
+	t.Setenv(CredentialsDirectoryEnv, "")
+	assert.Equal(t, DefaultSecretsDir, r.dir())
+}
+
+func TestSecretsDirectoryResolverCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	writeFiles(t, dir, map[string]string{"token": "first"})
+
+	r := &SecretsDirectoryResolver{Dir: dir}
+	val, _ := r.Resolve([]string{"token"}, nil)
+	assert.Equal(t, "first", val)
+
+	// the cached value is used while the file is unchanged
+	r.cache[path] = cachedSecret{modTime: r.cache[path].modTime, size: r.cache[path].size, value: "cached"}
+	val, _ = r.Resolve([]string{"token"}, nil)
+	assert.Equal(t, "cached", val)
+
+	// modifying the file invalidates the cached value
+	writeFiles(t, dir, map[string]string{"token": "second"})
+	modTime := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	val, _ = r.Resolve([]string{"token"}, nil)
+	assert.Equal(t, "second", val)
+
+	r.cache[path] = cachedSecret{modTime: r.cache[path].modTime, size: r.cache[path].size, value: "cached"}
+	r.Invalidate()
+	val, _ = r.Resolve([]string{"token"}, nil)
+	assert.Equal(t, "second", val)
+
+	// removing the file stops it from being resolved
+	assert.NoError(t, os.Remove(path))
+	_, ok := r.Resolve([]string{"token"}, nil)
+	assert.False(t, ok)
+}
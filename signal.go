@@ -0,0 +1,83 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// DefaultReloadDebounce is the period used to debounce reload signals when no
+// Debounce is specified in the provided SignalReloadOptions
+const DefaultReloadDebounce = 250 * time.Millisecond
+
+// SignalReloadOptions configures how a Venom instance is reloaded when it
+// receives a signal
+type SignalReloadOptions struct {
+	// Debounce is the period to wait after receiving a signal before
+	// reloading. Any further signals received within the period restart it,
+	// so that a burst of signals results in a single reload. The default is
+	// DefaultReloadDebounce.
+	Debounce time.Duration
+
+	// OnReload, if set, is called with the result of every reload
+	OnReload func(result ReloadResult)
+}
+
+// ReloadOnSignal reloads the provided Venom instance, as described by Reload,
+// whenever the process receives any of the provided signals, ie
+//
+//	venom.ReloadOnSignal(ctx, v, syscall.SIGHUP)
+//
+// Signals are handled in a separate goroutine until ctx is done.
+func ReloadOnSignal(ctx context.Context, v *Venom, signals ...os.Signal) {
+	ReloadOnSignalWith(ctx, v, SignalReloadOptions{}, signals...)
+}
+
+// ReloadOnSignalWith reloads the provided Venom instance whenever the process
+// receives any of the provided signals, as configured by the provided
+// SignalReloadOptions
+func ReloadOnSignalWith(ctx context.Context, v *Venom, opts SignalReloadOptions, signals ...os.Signal) {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	go func() {
+		defer signal.Stop(received)
+		reloadOnSignals(ctx, v, opts, received)
+	}()
+}
+
+// reloadOnSignals reloads the provided Venom instance once each burst of
+// signals received from the provided channel has been debounced, until ctx is
+// done
+func reloadOnSignals(ctx context.Context, v *Venom, opts SignalReloadOptions, received <-chan os.Signal) {
+	debounce := opts.Debounce
+	if debounce <= 0 {
+		debounce = DefaultReloadDebounce
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-received:
+			// restart the debounce period, draining the timer if it fired
+			// before it could be stopped
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(debounce)
+		case <-timer.C:
+			result := v.reloadAndReport()
+			if opts.OnReload != nil {
+				opts.OnReload(result)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadOnSignals(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json":      `{"name": "app", "db": {"host": "localhost", "user": "admin"}}`,
+		"conf.d/10-a.json": `{"db": {"port": 5432}}`,
+	})
+
+	v := NewSafe()
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+	assert.NoError(t, v.LoadDirectory(filepath.Join(dir, "conf.d"), false))
+
+	var mu sync.Mutex
+	var results []ReloadResult
+	reloaded := make(chan struct{}, 8)
+	opts := SignalReloadOptions{
+		Debounce: 20 * time.Millisecond,
+		OnReload: func(result ReloadResult) {
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+			reloaded <- struct{}{}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		reloadOnSignals(ctx, v, opts, received)
+		close(done)
+	}()
+
+	waitForReload := func(t *testing.T) {
+		select {
+		case <-reloaded:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for reload")
+		}
+	}
+
+	t.Run("should reload once for a burst of signals", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"config.json": `{"db": {"host": "example.com"}}`,
+		})
+		for i := 0; i < 5; i++ {
+			received <- os.Interrupt
+		}
+		waitForReload(t)
+
+		// ensure that no further reloads were triggered by the burst
+		select {
+		case <-reloaded:
+			t.Fatal("a burst of signals triggered multiple reloads")
+		case <-time.After(3 * opts.Debounce):
+		}
+		mu.Lock()
+		assert.Len(t, results, 1)
+		assert.NoError(t, results[0].Err)
+		mu.Unlock()
+
+		assert.Nil(t, v.Get("name"))
+		assert.Nil(t, v.Get("db.user"))
+		assert.Equal(t, "example.com", v.Get("db.host"))
+		assert.Equal(t, 5432.0, v.Get("db.port"))
+	})
+
+	t.Run("should report failed reloads", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"conf.d/20-b.json": `{"db": `,
+		})
+		received <- os.Interrupt
+		waitForReload(t)
+
+		mu.Lock()
+		assert.Len(t, results, 2)
+		assert.Error(t, results[1].Err)
+		mu.Unlock()
+		assert.Equal(t, "example.com", v.Get("db.host"))
+	})
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for signal handling to stop")
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+// +build !windows
+
+package venom
+
+import (
+	"context"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"name": "app"}`,
+	})
+
+	v := NewSafe()
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make(chan ReloadResult, 1)
+	ReloadOnSignalWith(ctx, v, SignalReloadOptions{
+		Debounce: 10 * time.Millisecond,
+		OnReload: func(result ReloadResult) { results <- result },
+	}, syscall.SIGHUP)
+
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"name": "reloaded"}`,
+	})
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+	select {
+	case result := <-results:
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "reloaded", v.Get("name"))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+}
@@ -0,0 +1,184 @@
+package venom
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"sync"
+)
+
+// SignatureExtension is the extension appended to the name of a config file to
+// find its detached signature, ie "config.json.sig" for "config.json"
+const SignatureExtension = ".sig"
+
+var (
+	// ErrUnsigned is the error wrapped by a SignatureError when a config file
+	// has no signature and strict signature verification is enabled
+	ErrUnsigned = errors.New("file is not signed")
+
+	// ErrInvalidSignature is the error wrapped by a SignatureError when the
+	// signature of a config file is not valid for any trusted key
+	ErrInvalidSignature = errors.New("signature is not valid for any trusted key")
+)
+
+// A SignatureError is returned when the signature of a config file can not be
+// verified
+type SignatureError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current SignatureError instance
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("venom: %s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the reason the signature could not be verified
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// signaturePolicy holds the keys used to verify config file signatures
+type signaturePolicy struct {
+	mu     sync.Mutex
+	keys   []ed25519.PublicKey
+	strict bool
+}
+
+// AddTrustedKeys adds the provided ed25519 public keys to the set of keys
+// trusted to sign config files.
+//
+// Once any key is trusted, every config file with a detached signature, ie
+// "config.json.sig" alongside "config.json", must be signed by one of the
+// trusted keys. Files without a signature are still loaded unless strict
+// signature verification is enabled via SetStrictSignatures.
+func (v *Venom) AddTrustedKeys(keys ...ed25519.PublicKey) {
+	v.signatures.mu.Lock()
+	defer v.signatures.mu.Unlock()
+	v.signatures.keys = append(v.signatures.keys, keys...)
+}
+
+// SetStrictSignatures toggles strict signature verification. When enabled,
+// config files loaded via LoadFile, LoadDirectory, LoadFS,
+// LoadKeyPerFileDirectory, or any of their variants, which are not signed by a
+// trusted key are rejected.
+func (v *Venom) SetStrictSignatures(strict bool) {
+	v.signatures.mu.Lock()
+	defer v.signatures.mu.Unlock()
+	v.signatures.strict = strict
+}
+
+// enabled returns true if config file signatures must be verified
+func (p *signaturePolicy) enabled() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.strict || len(p.keys) > 0
+}
+
+// verify verifies the signature of the named config file, containing data,
+// reading the signature with the provided function
+func (p *signaturePolicy) verify(name string, data []byte, read func(string) ([]byte, error)) error {
+	p.mu.Lock()
+	keys, strict := p.keys, p.strict
+	p.mu.Unlock()
+
+	encoded, err := read(name + SignatureExtension)
+	if errors.Is(err, fs.ErrNotExist) {
+		if strict {
+			return &SignatureError{Path: name, Err: ErrUnsigned}
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	signature, err := decodeSignature(encoded)
+	if err != nil {
+		return &SignatureError{Path: name, Err: err}
+	}
+
+	for _, key := range keys {
+		if ed25519.Verify(key, data, signature) {
+			return nil
+		}
+	}
+	return &SignatureError{Path: name, Err: ErrInvalidSignature}
+}
+
+// decodeSignature decodes a detached signature, which may either be raw or
+// base64 encoded
+func decodeSignature(encoded []byte) ([]byte, error) {
+	if len(encoded) == ed25519.SignatureSize {
+		return encoded, nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("malformed signature %s", SignatureExtension)
+	}
+	return signature, nil
+}
+
+// readSignedFile reads the named config file, opened with the provided
+// function, verifying its signature before loading it
+func (v *Venom) readSignedFile(name string, open func(string) (io.ReadCloser, error)) (map[string]interface{}, error) {
+	data, err := v.readVerifiedFile(name, open)
+	if err != nil {
+		return nil, err
+	}
+	return v.decodeFile(bytes.NewReader(data), name)
+}
+
+// readVerifiedFile reads the contents of the named file, opened with the
+// provided function, verifying its signature if signature verification is
+// enabled. Neither the file nor its signature are read beyond the MaxFileSize
+// set via SetLimits.
+func (v *Venom) readVerifiedFile(name string, open func(string) (io.ReadCloser, error)) ([]byte, error) {
+	limits := LoadLimits{MaxFileSize: v.Limits().MaxFileSize}
+	read := func(name string) ([]byte, error) {
+		file, err := open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		r, _ := limits.limitReader(file)
+		data, err := ioutil.ReadAll(r)
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			return nil, &LoadError{Path: name, Err: err}
+		}
+		return data, err
+	}
+
+	data, err := read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.signatures.enabled() {
+		if err := v.signatures.verify(name, data, read); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// SignFile signs the named config file with the provided ed25519 private key,
+// writing the base64 encoded detached signature alongside it, ie to
+// "config.json.sig" for "config.json"
+func SignFile(key ed25519.PrivateKey, name string) error {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	return ioutil.WriteFile(name+SignatureExtension, []byte(signature+"\n"), 0644)
+}
@@ -0,0 +1,172 @@
+package venom
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generateKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return public, private
+}
+
+func TestLoadSignedFile(t *testing.T) {
+	trusted, trustedKey := generateKey(t)
+	_, untrustedKey := generateKey(t)
+
+	testIO := []struct {
+		tc     string
+		sign   ed25519.PrivateKey
+		modify bool
+		strict bool
+		err    error
+		expect ConfigMap
+	}{
+		{
+			tc:     "should load a file signed by a trusted key",
+			sign:   trustedKey,
+			expect: ConfigMap{"foo": "bar"},
+		},
+		{
+			tc:     "should load an unsigned file when not strict",
+			expect: ConfigMap{"foo": "bar"},
+		},
+		{
+			tc:     "should reject an unsigned file when strict",
+			strict: true,
+			err:    ErrUnsigned,
+		},
+		{
+			tc:   "should reject a file signed by an untrusted key",
+			sign: untrustedKey,
+			err:  ErrInvalidSignature,
+		},
+		{
+			tc:     "should reject a file modified after signing",
+			sign:   trustedKey,
+			modify: true,
+			err:    ErrInvalidSignature,
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			dir := t.TempDir()
+			name := filepath.Join(dir, "config.json")
+			writeFiles(t, dir, map[string]string{"config.json": `{"foo": "bar"}`})
+			if test.sign != nil {
+				assert.NoError(t, SignFile(test.sign, name))
+			}
+			if test.modify {
+				writeFiles(t, dir, map[string]string{"config.json": `{"foo": "baz"}`})
+			}
+
+			v := New()
+			v.AddTrustedKeys(trusted)
+			v.SetStrictSignatures(test.strict)
+			err := v.LoadFile(name)
+
+			if test.err != nil {
+				var sigErr *SignatureError
+				assert.True(t, errors.As(err, &sigErr))
+				assert.True(t, errors.Is(err, test.err))
+				assert.Equal(t, name, sigErr.Path)
+			} else {
+				assert.NoError(t, err)
+			}
+			st := v.Store.(*DefaultConfigStore)
+			assert.EqualValues(t, test.expect, st.config[FileLevel])
+		})
+	}
+}
+
+func TestLoadSignedDirectory(t *testing.T) {
+	trusted, trustedKey := generateKey(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.json":        `{"foo": "bar", "$include": "shared/b.json"}`,
+		"shared/b.json": `{"baz": "qux"}`,
+	})
+	assert.NoError(t, SignFile(trustedKey, filepath.Join(dir, "a.json")))
+
+	v := New()
+	v.AddTrustedKeys(trusted)
+	v.SetStrictSignatures(true)
+	err := v.LoadDirectory(dir, false)
+	assert.True(t, errors.Is(err, ErrUnsigned), "included files must be signed")
+	assert.Nil(t, v.Get("foo"))
+
+	assert.NoError(t, SignFile(trustedKey, filepath.Join(dir, "shared", "b.json")))
+	assert.NoError(t, v.LoadDirectory(dir, false))
+	assert.Equal(t, "bar", v.Get("foo"))
+	assert.Equal(t, "qux", v.Get("baz"))
+}
+
+func TestLoadSignedFS(t *testing.T) {
+	trusted, trustedKey := generateKey(t)
+	data := []byte(`{"foo": "bar"}`)
+	fsys := fstest.MapFS{
+		"config.json":     {Data: data},
+		"config.json.sig": {Data: ed25519.Sign(trustedKey, data)},
+		"unsigned.json":   {Data: data},
+	}
+
+	v := New()
+	v.AddTrustedKeys(trusted)
+	v.SetStrictSignatures(true)
+	assert.NoError(t, v.LoadFS(fsys, "config.json"))
+	assert.Equal(t, "bar", v.Get("foo"))
+	assert.True(t, errors.Is(v.LoadFS(fsys, "unsigned.json"), ErrUnsigned))
+}
+
+func TestLoadSignedFileLimits(t *testing.T) {
+	trusted, trustedKey := generateKey(t)
+	dir := t.TempDir()
+	name := filepath.Join(dir, "config.json")
+	writeFiles(t, dir, map[string]string{"config.json": `{"data": "` + strings.Repeat("a", 64) + `"}`})
+	assert.NoError(t, SignFile(trustedKey, name))
+
+	v := New()
+	v.AddTrustedKeys(trusted)
+	v.SetLimits(LoadLimits{MaxFileSize: 32})
+	err := v.LoadFile(name)
+
+	var limitErr *LimitError
+	assert.True(t, errors.As(err, &limitErr), "%v", err)
+	var loadErr *LoadError
+	if assert.True(t, errors.As(err, &loadErr)) {
+		assert.Equal(t, name, loadErr.Path)
+	}
+	assert.Equal(t, 0, v.Size())
+}
+
+func TestSignFile(t *testing.T) {
+	public, private := generateKey(t)
+	dir := t.TempDir()
+	name := filepath.Join(dir, "config.json")
+	writeFiles(t, dir, map[string]string{"config.json": `{"foo": "bar"}`})
+
+	assert.NoError(t, SignFile(private, name))
+	encoded, err := ioutil.ReadFile(name + SignatureExtension)
+	assert.NoError(t, err)
+	signature, err := decodeSignature(encoded)
+	assert.NoError(t, err)
+	assert.True(t, ed25519.Verify(public, []byte(`{"foo": "bar"}`), signature))
+
+	writeFiles(t, dir, map[string]string{"config.json.sig": "not a signature"})
+	v := New()
+	v.AddTrustedKeys(public)
+	var sigErr *SignatureError
+	assert.True(t, errors.As(v.LoadFile(name), &sigErr))
+}
@@ -1,10 +1,10 @@
 package venom
 
 import (
-	"container/heap"
 	"encoding/json"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -23,6 +23,138 @@ type ConfigStore interface {
 	Size() int
 }
 
+// An Explainer is a ConfigStore which is capable of explaining which
+// ConfigLevel the value of a key was resolved from.
+//
+// ConfigStores which do not implement Explainer are explained using Find, so
+// their Explanations never identify a Level, File or Profile, nor report an
+// Err.
+type Explainer interface {
+	Explain(key string) Explanation
+}
+
+// explainKey explains the provided key using the Explain method of the provided
+// ConfigStore if it is an Explainer, falling back to Find if it is not
+func explainKey(s ConfigStore, key string) Explanation {
+	if explainer, ok := s.(Explainer); ok {
+		return explainer.Explain(key)
+	}
+	val, found := s.Find(key)
+	return Explanation{Key: key, Resolved: key, Value: val, Found: found}
+}
+
+// An Unsetter is a ConfigStore which is capable of removing a key from a
+// ConfigLevel.
+//
+// Keys are removed from a LevelStore which does not implement Unsetter by
+// replacing the contents of their ConfigLevel, while keys are removed from
+// other ConfigStores by setting their value to nil.
+type Unsetter interface {
+	Unset(level ConfigLevel, key string)
+}
+
+// unsetKey removes the provided key from the ConfigLevel l of the provided
+// ConfigStore
+func unsetKey(s ConfigStore, l ConfigLevel, key string) {
+	switch actual := s.(type) {
+	case Unsetter:
+		actual.Unset(l, key)
+	case LevelStore:
+		if data := actual.Level(l); data != nil {
+			unsetNested(data, strings.Split(key, Delim))
+			actual.ReplaceLevel(l, data)
+		}
+	default:
+		s.SetLevel(l, key, nil)
+	}
+}
+
+// A LevelStore is a ConfigStore which is capable of reading and replacing the
+// entire contents of a ConfigLevel.
+//
+// Reloaded config files are merged into the ConfigLevels of a ConfigStore which
+// does not implement LevelStore, meaning that keys removed from a file are not
+// removed from the ConfigStore until it is cleared.
+type LevelStore interface {
+	ReplaceLevel(l ConfigLevel, data ConfigMap)
+	Level(l ConfigLevel) ConfigMap
+}
+
+// replaceLevel replaces the contents of the ConfigLevel l of the provided
+// ConfigStore if it is a LevelStore, otherwise data is merged into the level
+func replaceLevel(s ConfigStore, l ConfigLevel, data ConfigMap) {
+	if ls, ok := s.(LevelStore); ok {
+		ls.ReplaceLevel(l, data)
+		return
+	}
+	s.Merge(l, data)
+}
+
+// A deferredReplacer is a ConfigStore which is capable of replacing the
+// contents of a ConfigLevel while deferring any notification of the change,
+// such as the events emitted by a SubscriptionStore, until the returned
+// function is called
+type deferredReplacer interface {
+	replaceLevelDeferred(l ConfigLevel, data ConfigMap) func()
+}
+
+// replaceLevelDeferred replaces the contents of the ConfigLevel l of the
+// provided ConfigStore in the same manner as replaceLevel, returning a function
+// which sends any notifications of the change
+func replaceLevelDeferred(s ConfigStore, l ConfigLevel, data ConfigMap) func() {
+	if d, ok := s.(deferredReplacer); ok {
+		return d.replaceLevelDeferred(l, data)
+	}
+	replaceLevel(s, l, data)
+	return func() {}
+}
+
+// storeLevel returns a copy of the ConfigLevel l of the provided ConfigStore,
+// or nil if the level is not allocated or the ConfigStore is not a LevelStore
+func storeLevel(s ConfigStore, l ConfigLevel) ConfigMap {
+	if ls, ok := s.(LevelStore); ok {
+		return ls.Level(l)
+	}
+	return nil
+}
+
+// A LevelLister is a ConfigStore which is capable of listing the ConfigLevels
+// which space has been allocated for.
+type LevelLister interface {
+	Levels() []ConfigLevel
+}
+
+// storeLevels returns the ConfigLevels of the provided ConfigStore in
+// increasing order of precedence, or nil if it is not a LevelLister
+func storeLevels(s ConfigStore) []ConfigLevel {
+	if lister, ok := s.(LevelLister); ok {
+		return lister.Levels()
+	}
+	return nil
+}
+
+// A Copier is a ConfigStore which is capable of copying itself, such that
+// writes to the copy do not affect the original ConfigStore.
+//
+// ConfigStores which do not implement Copier are copied into a
+// DefaultConfigStore containing the data of every ConfigLevel which they are
+// able to list, without any of their resolvers or aliases.
+type Copier interface {
+	Copy() ConfigStore
+}
+
+// copyStore returns a copy of the provided ConfigStore
+func copyStore(s ConfigStore) ConfigStore {
+	if copier, ok := s.(Copier); ok {
+		return copier.Copy()
+	}
+	c := NewDefaultConfigStore()
+	for _, level := range storeLevels(s) {
+		c.ReplaceLevel(level, storeLevel(s, level))
+	}
+	return c
+}
+
 // DefaultConfigStore is the minimum implementation of a ConfigStore. It is
 // capable of storing and managing arbitrary configuration keys and values.
 type DefaultConfigStore struct {
@@ -30,9 +162,9 @@ type DefaultConfigStore struct {
 	// ConfigLevel for prioritized retrieval
 	config ConfigLevelMap
 
-	// usedLevels is a sorted slice of all ConfigLevels currently stored in the
-	// config map
-	usedLevels *ConfigLevelHeap
+	// usedLevels is a slice of all ConfigLevels currently stored in the config
+	// map, sorted in decreasing order of precedence
+	usedLevels []ConfigLevel
 
 	// resolvers is the definitive list of any customer ConfigLevel resolvers
 	// provided to this Venom instance
@@ -45,10 +177,9 @@ type DefaultConfigStore struct {
 // NewDefaultConfigStore returns a newly allocated DefaultConfigStore.
 func NewDefaultConfigStore() *DefaultConfigStore {
 	return &DefaultConfigStore{
-		config:     make(ConfigLevelMap),
-		usedLevels: NewConfigLevelHeap(),
-		resolvers:  make(map[ConfigLevel]Resolver),
-		aliases:    make(map[string]string),
+		config:    make(ConfigLevelMap),
+		resolvers: make(map[ConfigLevel]Resolver),
+		aliases:   make(map[string]string),
 	}
 }
 
@@ -59,7 +190,7 @@ func NewDefaultConfigStore() *DefaultConfigStore {
 // of active config levels, it will be added automatically
 func (s *DefaultConfigStore) RegisterResolver(level ConfigLevel, r Resolver) {
 	s.resolvers[level] = r
-	heap.Push(s.usedLevels, level)
+	s.useLevel(level)
 }
 
 // Alias registers an alias for a given key. This allows consumers to access
@@ -79,6 +210,14 @@ func (s *DefaultConfigStore) SetLevel(level ConfigLevel, key string, value inter
 	s.setIfNotExists(level, key, value)
 }
 
+// Unset removes the provided key from the specified level, along with any
+// nested ConfigMaps which are left empty by its removal.
+func (s *DefaultConfigStore) Unset(level ConfigLevel, key string) {
+	if config, ok := s.config[level]; ok {
+		unsetNested(config, strings.Split(key, Delim))
+	}
+}
+
 // Find searches for the given key, returning the discovered value and a
 // boolean indicating whether or not the key was found
 func (s *DefaultConfigStore) Find(key string) (interface{}, bool) {
@@ -90,11 +229,62 @@ func (s *DefaultConfigStore) Find(key string) (interface{}, bool) {
 func (s *DefaultConfigStore) Merge(l ConfigLevel, data ConfigMap) {
 	if _, ok := s.config[l]; !ok {
 		s.config[l] = make(ConfigMap)
-		heap.Push(s.usedLevels, l)
+		s.useLevel(l)
 	}
 	s.config[l] = s.config[l].merge(data)
 }
 
+// ReplaceLevel replaces the entire contents of the ConfigLevel l with the
+// provided config map, allocating space for ConfigLevel l if the level hasn't
+// already been allocated.
+func (s *DefaultConfigStore) ReplaceLevel(l ConfigLevel, data ConfigMap) {
+	if _, ok := s.config[l]; !ok {
+		s.useLevel(l)
+	}
+	s.config[l] = make(ConfigMap).merge(data)
+}
+
+// Level returns a copy of the config map stored at the ConfigLevel l, or nil
+// if no space has been allocated for ConfigLevel l.
+func (s *DefaultConfigStore) Level(l ConfigLevel) ConfigMap {
+	data, ok := s.config[l]
+	if !ok {
+		return nil
+	}
+	return make(ConfigMap).merge(data)
+}
+
+// Levels returns every ConfigLevel which space has been allocated for, in
+// increasing order of precedence.
+func (s *DefaultConfigStore) Levels() []ConfigLevel {
+	levels := make([]ConfigLevel, 0, len(s.config))
+	for l := range s.config {
+		levels = append(levels, l)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	return levels
+}
+
+// Copy returns a new ConfigStore containing a copy of the config data, aliases
+// and resolvers of this ConfigStore.
+func (s *DefaultConfigStore) Copy() ConfigStore {
+	return s.copy()
+}
+
+func (s *DefaultConfigStore) copy() *DefaultConfigStore {
+	c := NewDefaultConfigStore()
+	for level, data := range s.config {
+		c.ReplaceLevel(level, data)
+	}
+	for level, r := range s.resolvers {
+		c.RegisterResolver(level, r)
+	}
+	for from, to := range s.aliases {
+		c.aliases[from] = to
+	}
+	return c
+}
+
 // Size returns the number of config levels stored in this ConfigStore.
 func (s *DefaultConfigStore) Size() int {
 	return len(s.config)
@@ -103,7 +293,7 @@ func (s *DefaultConfigStore) Size() int {
 func (s *DefaultConfigStore) setIfNotExists(l ConfigLevel, key string, value interface{}) {
 	if _, ok := s.config[l]; !ok {
 		s.config[l] = make(ConfigMap)
-		heap.Push(s.usedLevels, l)
+		s.useLevel(l)
 	}
 	setNested(s.config[l], strings.Split(key, Delim), value)
 }
@@ -127,6 +317,55 @@ func setNested(config ConfigMap, keys []string, value interface{}) {
 	}
 }
 
+// unsetNested removes the value stored in the nested keyspace as defined by the
+// delim separated keys, returning true if config is left empty
+func unsetNested(config ConfigMap, keys []string) bool {
+	key := keys[0]
+	if len(keys) == 1 {
+		delete(config, key)
+		return len(config) == 0
+	}
+
+	nested, ok := config[key].(ConfigMap)
+	if !ok {
+		return false
+	}
+	if unsetNested(nested, keys[1:]) {
+		delete(config, key)
+	}
+	return len(config) == 0
+}
+
+// Explain searches for the given key in the same manner as Find, returning an
+// Explanation of the ConfigLevel the value was resolved from. If an
+// ErrorResolver reports an error, the search stops and the error is returned
+// in the Explanation.
+func (s *DefaultConfigStore) Explain(key string) Explanation {
+	e := Explanation{Key: key, Resolved: key}
+	if actual, isAliased := s.aliases[key]; isAliased {
+		e.Resolved = actual
+	}
+
+	keys := strings.Split(e.Resolved, Delim)
+	for _, level := range s.usedLevels {
+		resolver, resolverExists := s.resolvers[level]
+		if !resolverExists {
+			resolver = defaultResolver
+		}
+
+		val, ok, err := resolveErr(resolver, keys, s.config[level])
+		if err != nil {
+			e.Level, e.Err = level, err
+			return e
+		}
+		if ok {
+			e.Value, e.Level, e.Found = val, level, true
+			return e
+		}
+	}
+	return e
+}
+
 func (s *DefaultConfigStore) find(key string) (val interface{}, ok bool) {
 	// check for aliases before beginning search
 	if actual, isAliased := s.aliases[key]; isAliased {
@@ -134,7 +373,7 @@ func (s *DefaultConfigStore) find(key string) (val interface{}, ok bool) {
 	}
 
 	keys := strings.Split(key, Delim)
-	for _, level := range *s.usedLevels {
+	for _, level := range s.usedLevels {
 		resolver, resolverExists := s.resolvers[level]
 		if !resolverExists {
 			resolver = defaultResolver
@@ -147,11 +386,24 @@ func (s *DefaultConfigStore) find(key string) (val interface{}, ok bool) {
 	return nil, false
 }
 
-// Clear removes all data from the ConfigLevelMap and resets the heap of config
+// Clear removes all data from the ConfigLevelMap and resets the used config
 // levels.
 func (s *DefaultConfigStore) Clear() {
 	s.config = make(ConfigLevelMap)
-	s.usedLevels = NewConfigLevelHeap()
+	s.usedLevels = nil
+}
+
+// useLevel adds the provided ConfigLevel to the used levels, unless it is
+// already present, retaining their order of precedence
+func (s *DefaultConfigStore) useLevel(l ConfigLevel) {
+	index := sort.Search(len(s.usedLevels), func(i int) bool { return s.usedLevels[i] <= l })
+	if index < len(s.usedLevels) && s.usedLevels[index] == l {
+		return
+	}
+
+	s.usedLevels = append(s.usedLevels, 0)
+	copy(s.usedLevels[index+1:], s.usedLevels[index:])
+	s.usedLevels[index] = l
 }
 
 // Debug returns the current venom ConfigLevelMap as a pretty-printed JSON
@@ -195,6 +447,14 @@ func (s *SafeConfigStore) SetLevel(level ConfigLevel, key string, value interfac
 	s.c.SetLevel(level, key, value)
 }
 
+// Unset removes the provided key from the specified level, along with any
+// nested ConfigMaps which are left empty by its removal.
+func (s *SafeConfigStore) Unset(level ConfigLevel, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.Unset(level, key)
+}
+
 // Merge merges the provided config map into the ConfigLevel l, allocating
 // space for ConfigLevel l if the level hasn't already been allocated.
 func (s *SafeConfigStore) Merge(l ConfigLevel, data ConfigMap) {
@@ -203,6 +463,39 @@ func (s *SafeConfigStore) Merge(l ConfigLevel, data ConfigMap) {
 	s.c.Merge(l, data)
 }
 
+// ReplaceLevel replaces the entire contents of the ConfigLevel l with the
+// provided config map, allocating space for ConfigLevel l if the level hasn't
+// already been allocated.
+func (s *SafeConfigStore) ReplaceLevel(l ConfigLevel, data ConfigMap) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.ReplaceLevel(l, data)
+}
+
+// Level returns a copy of the config map stored at the ConfigLevel l, or nil
+// if no space has been allocated for ConfigLevel l.
+func (s *SafeConfigStore) Level(l ConfigLevel) ConfigMap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.Level(l)
+}
+
+// Levels returns every ConfigLevel which space has been allocated for, in
+// increasing order of precedence.
+func (s *SafeConfigStore) Levels() []ConfigLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.Levels()
+}
+
+// Copy returns a new SafeConfigStore containing a copy of the config data,
+// aliases and resolvers of this ConfigStore.
+func (s *SafeConfigStore) Copy() ConfigStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &SafeConfigStore{c: s.c.copy()}
+}
+
 // Alias registers an alias for a given key. This allows consumers to access
 // the same config via a different key, increasing the backwards
 // compatibility of an application.
@@ -220,7 +513,15 @@ func (s *SafeConfigStore) Find(key string) (interface{}, bool) {
 	return s.c.Find(key)
 }
 
-// Clear removes all data from the ConfigLevelMap and resets the heap of config
+// Explain searches for the given key in the same manner as Find, returning an
+// Explanation of the ConfigLevel the value was resolved from.
+func (s *SafeConfigStore) Explain(key string) Explanation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.Explain(key)
+}
+
+// Clear removes all data from the ConfigLevelMap and resets the used config
 // levels.
 func (s *SafeConfigStore) Clear() {
 	s.mu.Lock()
@@ -283,12 +584,53 @@ func (l *LoggableConfigStore) SetLevel(level ConfigLevel, key string, value inte
 	l.log.LogWrite(level, key, value)
 }
 
+// Unset removes the provided key from the specified level. The removal is
+// logged as a write of a nil value.
+func (l *LoggableConfigStore) Unset(level ConfigLevel, key string) {
+	unsetKey(l.c, level, key)
+	l.log.LogWrite(level, key, nil)
+}
+
 // Merge merges the provided config map into the ConfigLevel l, allocating
 // space for ConfigLevel l if the level hasn't already been allocated.
 func (l *LoggableConfigStore) Merge(cl ConfigLevel, data ConfigMap) {
 	l.c.Merge(cl, data)
 }
 
+// ReplaceLevel replaces the entire contents of the ConfigLevel cl with the
+// provided config map, allocating space for ConfigLevel cl if the level hasn't
+// already been allocated.
+func (l *LoggableConfigStore) ReplaceLevel(cl ConfigLevel, data ConfigMap) {
+	replaceLevel(l.c, cl, data)
+}
+
+// Level returns a copy of the config map stored at the ConfigLevel cl, or nil
+// if no space has been allocated for ConfigLevel cl.
+func (l *LoggableConfigStore) Level(cl ConfigLevel) ConfigMap {
+	return storeLevel(l.c, cl)
+}
+
+// Levels returns every ConfigLevel which space has been allocated for, in
+// increasing order of precedence.
+func (l *LoggableConfigStore) Levels() []ConfigLevel {
+	return storeLevels(l.c)
+}
+
+// Copy returns a new ConfigStore containing a copy of the config data, aliases
+// and resolvers of this ConfigStore. Reads and writes of the copy are not
+// logged.
+func (l *LoggableConfigStore) Copy() ConfigStore {
+	return copyStore(l.c)
+}
+
+// LogReload logs the result of a reload if the Logger of this store is a
+// ReloadLogger.
+func (l *LoggableConfigStore) LogReload(result ReloadResult) {
+	if logger, ok := l.log.(ReloadLogger); ok {
+		logger.LogReload(result)
+	}
+}
+
 // Alias registers an alias for a given key. This allows consumers to access
 // the same config via a different key, increasing the backwards
 // compatibility of an application.
@@ -304,7 +646,13 @@ func (l *LoggableConfigStore) Find(key string) (interface{}, bool) {
 	return a, b
 }
 
-// Clear removes all data from the ConfigLevelMap and resets the heap of config
+// Explain searches for the given key in the same manner as Find, returning an
+// Explanation of the ConfigLevel the value was resolved from.
+func (l *LoggableConfigStore) Explain(key string) Explanation {
+	return explainKey(l.c, key)
+}
+
+// Clear removes all data from the ConfigLevelMap and resets the used config
 // levels.
 func (l *LoggableConfigStore) Clear() {
 	l.c.Clear()
@@ -1,6 +1,11 @@
 package venom
 
-import "testing"
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestConfigStoreSetAndFind(t *testing.T) {
 	t.Parallel()
@@ -121,3 +126,125 @@ func TestConfigStoreEdgeCases(t *testing.T) {
 		testEdgeCases(t, store)
 	})
 }
+
+func TestConfigStorePrecedence(t *testing.T) {
+	s := NewDefaultConfigStore()
+	for _, level := range []ConfigLevel{FileLevel, DefaultLevel, OverrideLevel, EnvironmentLevel, FileLevel} {
+		s.SetLevel(level, "foo", level)
+	}
+	s.RegisterResolver(FileLevel, defaultResolver)
+	s.RegisterResolver(FlagLevel, defaultResolver)
+
+	assert.Equal(t, []ConfigLevel{OverrideLevel, FlagLevel, EnvironmentLevel, FileLevel, DefaultLevel}, s.usedLevels)
+	foo, _ := s.Find("foo")
+	assert.Equal(t, OverrideLevel, foo)
+
+	s.Clear()
+	s.SetLevel(DefaultLevel, "foo", DefaultLevel)
+	s.SetLevel(FileLevel, "foo", FileLevel)
+	foo, _ = s.Find("foo")
+	assert.Equal(t, FileLevel, foo)
+}
+
+func TestConfigStoreReplaceLevel(t *testing.T) {
+	t.Parallel()
+	t.Run("DefaultConfigStore", func(t *testing.T) {
+		testReplaceLevel(t, NewDefaultConfigStore())
+	})
+	t.Run("SafeConfigStore", func(t *testing.T) {
+		testReplaceLevel(t, NewSafeConfigStore().(*SafeConfigStore))
+	})
+	t.Run("LoggableConfigStore", func(t *testing.T) {
+		testReplaceLevel(t, NewLoggableWith(&TestLogger{}))
+	})
+	t.Run("Venom", func(t *testing.T) {
+		testReplaceLevel(t, New())
+	})
+	t.Run("SafeVenom", func(t *testing.T) {
+		testReplaceLevel(t, NewSafe())
+	})
+	t.Run("SubscriptionStore", func(t *testing.T) {
+		store, clear := NewSubscriptionStore(NewDefaultConfigStore())
+		defer clear()
+		testReplaceLevel(t, store)
+	})
+	t.Run("JournalStore", func(t *testing.T) {
+		store, err := NewJournalStore(NewDefaultConfigStore(), filepath.Join(t.TempDir(), "journal"))
+		assert.NoError(t, err)
+		defer store.Close()
+		testReplaceLevel(t, store)
+	})
+}
+
+func TestConfigStoreUnset(t *testing.T) {
+	t.Parallel()
+	t.Run("DefaultConfigStore", func(t *testing.T) {
+		testUnset(t, NewDefaultConfigStore())
+	})
+	t.Run("SafeConfigStore", func(t *testing.T) {
+		testUnset(t, NewSafeConfigStore().(*SafeConfigStore))
+	})
+	t.Run("LoggableConfigStore", func(t *testing.T) {
+		testUnset(t, NewLoggableWith(&TestLogger{}))
+	})
+	t.Run("Venom", func(t *testing.T) {
+		testUnset(t, New())
+	})
+	t.Run("SafeVenom", func(t *testing.T) {
+		testUnset(t, NewSafe())
+	})
+	t.Run("SubscriptionStore", func(t *testing.T) {
+		store, clear := NewSubscriptionStore(NewDefaultConfigStore())
+		defer clear()
+		testUnset(t, store)
+	})
+	t.Run("JournalStore", func(t *testing.T) {
+		store, err := NewJournalStore(NewDefaultConfigStore(), filepath.Join(t.TempDir(), "journal"))
+		assert.NoError(t, err)
+		defer store.Close()
+		testUnset(t, store)
+	})
+}
+
+func TestCopyMinimalStore(t *testing.T) {
+	s := NewDefaultConfigStore()
+	s.SetLevel(FileLevel, "db.host", "localhost")
+	s.Alias("host", "db.host")
+
+	c := copyStore(minimalStore{s})
+	c.SetLevel(FileLevel, "db.host", "copy")
+
+	host, _ := s.Find("db.host")
+	assert.Equal(t, "localhost", host)
+	host, _ = c.Find("db.host")
+	assert.Equal(t, "copy", host)
+
+	// aliases are not copied from stores which are not Copiers
+	_, ok := c.Find("host")
+	assert.False(t, ok)
+}
+
+func TestUnsetFallbacks(t *testing.T) {
+	t.Run("LevelStore", func(t *testing.T) {
+		s := NewDefaultConfigStore()
+		store := struct {
+			ConfigStore
+			LevelStore
+		}{s, s}
+		store.SetLevel(OverrideLevel, "db.host", "localhost")
+		store.SetLevel(OverrideLevel, "db.user", "admin")
+
+		unsetKey(store, OverrideLevel, "db.host")
+		assert.Equal(t, ConfigMap{"db": ConfigMap{"user": "admin"}}, s.Level(OverrideLevel))
+	})
+	t.Run("ConfigStore", func(t *testing.T) {
+		v := NewWithStore(minimalStore{NewDefaultConfigStore()})
+		v.SetDefault("db.host", "localhost")
+		v.SetOverride("db.host", "example.com")
+
+		v.Unset(OverrideLevel, "db.host")
+		val, ok := v.Find("db.host")
+		assert.True(t, ok)
+		assert.Nil(t, val)
+	})
+}
@@ -2,6 +2,8 @@ package venom
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -68,12 +70,88 @@ func (s *SubscriptionStore) SetLevel(level ConfigLevel, key string, value interf
 	s.emit(key, value)
 }
 
+// Unset removes the provided key from the specified level.
+//
+// Once removed, an event with a nil Value will be emitted by this Subscription
+// store, if any subscribers exist for the provided key.
+func (s *SubscriptionStore) Unset(level ConfigLevel, key string) {
+	unsetKey(s.store, level, key)
+	s.emit(key, nil)
+}
+
 // Merge merges the provided config map into the ConfigLevel l, allocating
 // space for ConfigLevel l if the level hasn't already been allocated.
 func (s *SubscriptionStore) Merge(l ConfigLevel, data ConfigMap) {
 	s.store.Merge(l, data)
 }
 
+// ReplaceLevel replaces the entire contents of the ConfigLevel l with the
+// provided config map, allocating space for ConfigLevel l if the level hasn't
+// already been allocated.
+//
+// Once replaced, an event will be emitted for every key within the level whose
+// value was added, changed or removed, in lexical order of the keys. Removed
+// keys are emitted with a nil Value.
+func (s *SubscriptionStore) ReplaceLevel(l ConfigLevel, data ConfigMap) {
+	s.replaceLevelDeferred(l, data)()
+}
+
+// replaceLevelDeferred replaces the contents of the ConfigLevel l, returning a
+// function which emits the events for the change
+func (s *SubscriptionStore) replaceLevelDeferred(l ConfigLevel, data ConfigMap) func() {
+	previous := flattenConfigMap(storeLevel(s.store, l))
+	replaceLevel(s.store, l, data)
+	current := flattenConfigMap(make(ConfigMap).merge(data))
+
+	changed := make([]string, 0, len(current))
+	for key, val := range current {
+		if old, ok := previous[key]; !ok || !reflect.DeepEqual(old, val) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	return func() {
+		for _, key := range changed {
+			s.emit(key, current[key])
+		}
+	}
+}
+
+// Level returns a copy of the config map stored at the ConfigLevel l, or nil
+// if no space has been allocated for ConfigLevel l.
+func (s *SubscriptionStore) Level(l ConfigLevel) ConfigMap {
+	return storeLevel(s.store, l)
+}
+
+// Levels returns every ConfigLevel which space has been allocated for, in
+// increasing order of precedence.
+func (s *SubscriptionStore) Levels() []ConfigLevel {
+	return storeLevels(s.store)
+}
+
+// Copy returns a new ConfigStore containing a copy of the config data, aliases
+// and resolvers of the wrapped ConfigStore. Updates to the copy do not emit
+// any events.
+func (s *SubscriptionStore) Copy() ConfigStore {
+	return copyStore(s.store)
+}
+
+// LogReload emits an event for the result of a reload, with a Key of
+// ReloadEventKey and a ReloadResult Value. The result is also logged if the
+// wrapped ConfigStore is a ReloadLogger.
+func (s *SubscriptionStore) LogReload(result ReloadResult) {
+	if logger, ok := s.store.(ReloadLogger); ok {
+		logger.LogReload(result)
+	}
+	s.emit(ReloadEventKey, result)
+}
+
 // Alias registers an alias for a given key. This allows consumers to access
 // the same config via a different key, increasing the backwards
 // compatibility of an application.
@@ -87,6 +165,12 @@ func (s *SubscriptionStore) Find(key string) (interface{}, bool) {
 	return s.store.Find(key)
 }
 
+// Explain searches for the given key in the same manner as Find, returning an
+// Explanation of the ConfigLevel the value was resolved from.
+func (s *SubscriptionStore) Explain(key string) Explanation {
+	return explainKey(s.store, key)
+}
+
 // Clear removes all data from the ConfigLevelMap and resets the heap of config
 // levels.
 func (s *SubscriptionStore) Clear() {
@@ -179,3 +263,24 @@ func (s *SubscriptionStore) emit(key string, value interface{}) {
 		}
 	}
 }
+
+// flattenConfigMap returns the values of the provided config map keyed by their
+// full, Delim separated, key
+func flattenConfigMap(data ConfigMap) map[string]interface{} {
+	flat := make(map[string]interface{})
+	var walk func(prefix string, data ConfigMap)
+	walk = func(prefix string, data ConfigMap) {
+		for key, val := range data {
+			if prefix != "" {
+				key = prefix + Delim + key
+			}
+			if nested, ok := val.(ConfigMap); ok {
+				walk(key, nested)
+				continue
+			}
+			flat[key] = val
+		}
+	}
+	walk("", data)
+	return flat
+}
@@ -66,6 +66,34 @@ func TestSubscriptionStore_Subscribe(t *testing.T) {
 				ven.SetOverride("db.host", "example.com")
 			},
 		},
+		{
+			name: "should track changed keys when a level is replaced",
+			init: func(ven *Venom) {
+				ven.SetDefault("db.host", "localhost")
+				ven.SetDefault("db.port", "1234")
+				ven.SetDefault("db.user", "admin")
+			},
+			subscribeKey: "db",
+			expect: []Event{
+				{
+					Key:   "db.host",
+					Value: "example.com",
+				},
+				{
+					Key:   "db.name",
+					Value: "app",
+				},
+				{
+					Key:   "db.user",
+					Value: nil,
+				},
+			},
+			updates: func(ven *Venom) {
+				ven.ReplaceLevel(DefaultLevel, ConfigMap{
+					"db": ConfigMap{"host": "example.com", "port": "1234", "name": "app"},
+				})
+			},
+		},
 	}
 
 	for _, test := range testIO {
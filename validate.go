@@ -0,0 +1,60 @@
+package venom
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A Validator inspects a set of configs, returning an error if they are
+// invalid. Validators are run against a staging copy of the configs whenever
+// config files are reloaded, and must not modify the provided Venom instance.
+type Validator func(v *Venom) error
+
+// A ValidationError is returned by Reload when a Validator rejects the reloaded
+// configs
+type ValidationError struct {
+	Err error
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current ValidationError instance
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("venom: invalid config: %s", e.Err)
+}
+
+// Unwrap returns the error returned by the Validator
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validatorList holds the Validators registered with a Venom instance
+type validatorList struct {
+	mu         sync.Mutex
+	validators []Validator
+}
+
+// RegisterValidator registers a Validator which is run, in the order that
+// Validators were registered, against the reloaded configs before they replace
+// the current configs. If any Validator returns an error, the reload is
+// abandoned. See Reload for more details.
+func (v *Venom) RegisterValidator(fn Validator) {
+	v.validators.mu.Lock()
+	defer v.validators.mu.Unlock()
+	v.validators.validators = append(v.validators.validators, fn)
+}
+
+// validate runs every registered Validator against the provided ConfigStore,
+// returning a ValidationError for the first Validator to fail
+func (v *Venom) validate(staging ConfigStore) error {
+	v.validators.mu.Lock()
+	validators := append([]Validator(nil), v.validators.validators...)
+	v.validators.mu.Unlock()
+
+	staged := NewWithStore(staging)
+	for _, fn := range validators {
+		if err := fn(staged); err != nil {
+			return &ValidationError{Err: err}
+		}
+	}
+	return nil
+}
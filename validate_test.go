@@ -0,0 +1,112 @@
+package venom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// reloadRecorder is a Logger which records the result of each reload
+type reloadRecorder struct {
+	TestLogger
+	results []ReloadResult
+}
+
+func (r *reloadRecorder) LogReload(result ReloadResult) {
+	r.results = append(r.results, result)
+}
+
+func TestReloadValidation(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.json")
+	writeFiles(t, dir, map[string]string{
+		"config.json": `{"db": {"host": "localhost", "port": 5432}}`,
+	})
+
+	logger := &reloadRecorder{}
+	store, clear := NewSubscriptionStoreWithSize(NewLoggableConfigStoreWith(logger), 8)
+	defer clear()
+	reloads := store.Subscribe(ReloadEventKey)
+
+	v := NewWithStore(store)
+	assert.NoError(t, v.LoadFile(config))
+
+	var validated []interface{}
+	v.RegisterValidator(func(staged *Venom) error {
+		port := staged.Get("db.port")
+		validated = append(validated, port)
+		if port.(float64) < 1024 {
+			return fmt.Errorf("db.port %v is a privileged port", port)
+		}
+		return nil
+	})
+
+	t.Run("should reject invalid configs", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"config.json": `{"db": {"host": "example.com", "port": 80}}`,
+		})
+
+		err := v.Reload()
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr), "%v", err) {
+			assert.Contains(t, err.Error(), "privileged port")
+		}
+
+		assert.Equal(t, []interface{}{80.0}, validated)
+		assert.Equal(t, "localhost", v.Get("db.host"))
+		assert.Equal(t, 5432.0, v.Get("db.port"))
+
+		if assert.Len(t, logger.results, 1) {
+			assert.Equal(t, err, logger.results[0].Err)
+			assert.Equal(t, []string{config}, logger.results[0].Files)
+			assert.Empty(t, logger.results[0].Levels)
+		}
+
+		event := <-reloads
+		assert.Equal(t, ReloadEventKey, event.Key)
+		assert.Equal(t, err, event.Value.(ReloadResult).Err)
+	})
+
+	t.Run("should reject configs which fail to parse", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"config.json": `{"db": `,
+		})
+
+		assert.Error(t, v.Reload())
+		assert.Len(t, validated, 1)
+		assert.Equal(t, "localhost", v.Get("db.host"))
+		assert.Error(t, (<-reloads).Value.(ReloadResult).Err)
+	})
+
+	t.Run("should apply valid configs", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"config.json": `{"db": {"host": "example.com", "port": 6543}}`,
+		})
+
+		assert.NoError(t, v.Reload())
+		assert.Equal(t, "example.com", v.Get("db.host"))
+		assert.Equal(t, 6543.0, v.Get("db.port"))
+
+		result := (<-reloads).Value.(ReloadResult)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []ConfigLevel{FileLevel}, result.Levels)
+		assert.Len(t, logger.results, 3)
+	})
+}
+
+func TestStoreLoggerLogReload(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStoreLogger(log.New(&buf, "", 0)).(*StoreLogger)
+
+	logger.LogReload(ReloadResult{Files: []string{"config.json"}, Levels: []ConfigLevel{FileLevel}})
+	assert.Contains(t, buf.String(), "reloading files=1 levels=[1] status=ok")
+
+	buf.Reset()
+	logger.LogReload(ReloadResult{Err: errors.New("bad config")})
+	assert.Contains(t, buf.String(), "reloading files=0 levels=[] status=bad config")
+}
@@ -40,23 +40,12 @@ type ConfigMap map[string]interface{}
 func (c ConfigMap) merge(d ConfigMap) ConfigMap {
 	for key, val := range d {
 		switch actual := val.(type) {
+		case ConfigMap:
+			c[key] = c.nested(key).merge(actual)
 		case map[string]interface{}:
-			var existing ConfigMap
-			if _, ok := c[key]; !ok {
-				existing = make(ConfigMap)
-			} else {
-				existing = c[key].(ConfigMap)
-			}
-
-			c[key] = existing.merge(actual)
+			c[key] = c.nested(key).merge(actual)
 		case map[interface{}]interface{}:
-			var existing ConfigMap
-			if _, ok := c[key]; !ok {
-				existing = make(ConfigMap)
-			} else {
-				existing = c[key].(ConfigMap)
-			}
-			c[key] = existing.merge(mapInterfaceInterfaceToStrInterface(actual))
+			c[key] = c.nested(key).merge(mapInterfaceInterfaceToStrInterface(actual))
 		default:
 			c[key] = val
 		}
@@ -64,6 +53,15 @@ func (c ConfigMap) merge(d ConfigMap) ConfigMap {
 	return c
 }
 
+// nested returns the ConfigMap stored at key, or a newly allocated ConfigMap if
+// no ConfigMap is stored there
+func (c ConfigMap) nested(key string) ConfigMap {
+	if existing, ok := c[key].(ConfigMap); ok {
+		return existing
+	}
+	return make(ConfigMap)
+}
+
 func mapInterfaceInterfaceToStrInterface(src map[interface{}]interface{}) map[string]interface{} {
 	data := make(map[string]interface{})
 	for key, value := range src {
@@ -82,6 +80,28 @@ type ConfigLevelMap map[ConfigLevel]ConfigMap
 // arbitrary configuration keys and values.
 type Venom struct {
 	Store ConfigStore
+
+	// files tracks the config files which have been loaded into this Venom
+	// instance
+	files fileLayers
+
+	// search holds the config name and paths used by ReadInConfig
+	search configSearch
+
+	// profiles holds the sources of the active config profiles
+	profiles profileSettings
+
+	// validators holds the Validators run against reloaded configs
+	validators validatorList
+
+	// signatures holds the keys trusted to sign config files
+	signatures signaturePolicy
+
+	// envBindings holds the environment variable names bound via BindEnv
+	envBindings EnvBindings
+
+	// limits holds the LoadLimits enforced when loading config data
+	limits loadLimits
 }
 
 // New returns a newly initialized Venom instance.
@@ -125,7 +145,7 @@ func NewWithStore(s ConfigStore) *Venom {
 // configuration applied to it.
 func Default() *Venom {
 	ven := New()
-	ven.RegisterResolver(EnvironmentLevel, defaultEnvResolver)
+	ven.RegisterResolver(EnvironmentLevel, ven.defaultEnvResolver())
 	return ven
 }
 
@@ -133,10 +153,16 @@ func Default() *Venom {
 // resolver configuration applied to it.
 func DefaultSafe() *Venom {
 	ven := NewSafe()
-	ven.RegisterResolver(EnvironmentLevel, defaultEnvResolver)
+	ven.RegisterResolver(EnvironmentLevel, ven.defaultEnvResolver())
 	return ven
 }
 
+// defaultEnvResolver returns the EnvironmentVariableResolver registered by
+// Default, which uses the bindings added via BindEnv
+func (v *Venom) defaultEnvResolver() *EnvironmentVariableResolver {
+	return &EnvironmentVariableResolver{Bindings: v.Bindings()}
+}
+
 // RegisterResolver registers a custom config resolver for the specified
 // ConfigLevel.
 //
@@ -160,6 +186,13 @@ func (v *Venom) SetLevel(level ConfigLevel, key string, value interface{}) {
 	v.Store.SetLevel(level, key, value)
 }
 
+// Unset removes the provided key from the specified level of the config
+// collection. If the wrapped ConfigStore is neither an Unsetter nor a
+// LevelStore, the key is set to nil instead.
+func (v *Venom) Unset(level ConfigLevel, key string) {
+	unsetKey(v.Store, level, key)
+}
+
 // SetDefault sets the provided key and value into the DefaultLevel of the
 // config collection.
 func (v *Venom) SetDefault(key string, value interface{}) {
@@ -184,16 +217,55 @@ func (v *Venom) Find(key string) (interface{}, bool) {
 	return v.Store.Find(key)
 }
 
+// Lookup searches for the given key in the same manner as Find, additionally
+// returning any error reported by an ErrorResolver, such as an
+// EnvironmentVariableResolver which can not read the file named by an
+// environment variable. The search stops at the first error.
+func (v *Venom) Lookup(key string) (interface{}, bool, error) {
+	e := explainKey(v.Store, key)
+	return e.Value, e.Found, e.Err
+}
+
 // Merge merges the provided config map into the ConfigLevel l, allocating
 // space for ConfigLevel l if the level hasn't already been allocated.
 func (v *Venom) Merge(l ConfigLevel, data ConfigMap) {
 	v.Store.Merge(l, data)
 }
 
+// ReplaceLevel replaces the entire contents of the ConfigLevel l with the
+// provided config map, allocating space for ConfigLevel l if the level hasn't
+// already been allocated. If the wrapped ConfigStore is not a LevelStore, the
+// config map is merged into ConfigLevel l instead.
+func (v *Venom) ReplaceLevel(l ConfigLevel, data ConfigMap) {
+	replaceLevel(v.Store, l, data)
+}
+
+// Level returns a copy of the config map stored at the ConfigLevel l, or nil
+// if no space has been allocated for ConfigLevel l or the wrapped ConfigStore
+// is not a LevelStore.
+func (v *Venom) Level(l ConfigLevel) ConfigMap {
+	return storeLevel(v.Store, l)
+}
+
+// Levels returns every ConfigLevel which space has been allocated for, in
+// increasing order of precedence, or nil if the wrapped ConfigStore is not a
+// LevelLister.
+func (v *Venom) Levels() []ConfigLevel {
+	return storeLevels(v.Store)
+}
+
+// Copy returns a new ConfigStore containing a copy of the config data, aliases
+// and resolvers of the wrapped ConfigStore. If the wrapped ConfigStore is not a
+// Copier, only its config data is copied.
+func (v *Venom) Copy() ConfigStore {
+	return copyStore(v.Store)
+}
+
 // Clear removes all data from the ConfigLevelMap and resets the heap of config
 // levels
 func (v *Venom) Clear() {
 	v.Store.Clear()
+	v.files.reset()
 }
 
 // Debug returns the current venom ConfigLevelMap as a pretty-printed JSON
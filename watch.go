@@ -0,0 +1,154 @@
+package venom
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"os"
+	"time"
+)
+
+// WatchFiles polls every file and directory loaded via LoadFile, LoadDirectory,
+// or any of their variants, at the provided interval until ctx is done. Files
+// are tracked by their modification time and a hash of their contents, and
+// when any file is modified, created within a watched directory, or deleted,
+// the configs are reloaded as described by Reload.
+//
+// Errors encountered while reloading are sent on the returned channel, which is
+// closed once ctx is done. The channel is buffered, and errors are dropped
+// rather than blocking the watcher if the channel is not being received from.
+//
+// When using a SubscriptionStore, an event is emitted for every key whose value
+// changes within a reloaded ConfigLevel. As reloads happen in a separate
+// goroutine, a goroutine-safe ConfigStore, such as the one used by NewSafe,
+// should be used with WatchFiles.
+func (v *Venom) WatchFiles(ctx context.Context, interval time.Duration) <-chan error {
+	w := &fileWatcher{v: v}
+	w.poll()
+
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !w.poll() {
+				continue
+			}
+			if err := v.Reload(); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+		}
+	}()
+	return errs
+}
+
+// A fileStamp identifies the contents of a watched file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// A fileWatcher detects changes to the files loaded into a Venom instance
+type fileWatcher struct {
+	v      *Venom
+	stamps map[string]fileStamp
+}
+
+// poll stamps every watched file, returning true if any file has been created,
+// modified or deleted since the previous poll
+func (w *fileWatcher) poll() bool {
+	stamps := make(map[string]fileStamp)
+	changed := false
+	for _, name := range w.v.files.watchedPaths(w.v) {
+		if _, ok := stamps[name]; ok {
+			continue
+		}
+
+		previous, seen := w.stamps[name]
+		stamp, err := stampFile(name, previous)
+		if err != nil {
+			// missing or unreadable files are treated as deleted
+			continue
+		}
+
+		stamps[name] = stamp
+		if !seen || stamp.hash != previous.hash {
+			changed = true
+		}
+	}
+
+	for name := range w.stamps {
+		if _, ok := stamps[name]; !ok {
+			changed = true
+		}
+	}
+
+	w.stamps = stamps
+	return changed
+}
+
+// stampFile returns the fileStamp of the named file. The contents of the file
+// are only hashed if its modification time or size differ from the previous
+// stamp.
+func stampFile(name string, previous fileStamp) (fileStamp, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return fileStamp{}, err
+	}
+
+	stamp := fileStamp{modTime: info.ModTime(), size: info.Size()}
+	if stamp.modTime.Equal(previous.modTime) && stamp.size == previous.size {
+		stamp.hash = previous.hash
+		return stamp, nil
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return fileStamp{}, err
+	}
+	copy(stamp.hash[:], hash.Sum(nil))
+	return stamp, nil
+}
+
+// watchedPaths returns the paths of every file which currently makes up one of
+// the loaded sources, along with any files they include
+func (f *fileLayers) watchedPaths(v *Venom) []string {
+	f.mu.Lock()
+	sources := append([]configSource(nil), f.sources...)
+	var paths []string
+	for _, layer := range f.layers {
+		if layer.source != nil {
+			paths = append(paths, layer.name)
+		}
+	}
+	f.mu.Unlock()
+
+	for _, src := range sources {
+		paths = append(paths, src.paths(v)...)
+	}
+
+	if v.signatures.enabled() {
+		for _, name := range paths {
+			paths = append(paths, name+SignatureExtension)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,93 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.json":      `{"db": {"host": "localhost", "user": "admin"}}`,
+		"conf.d/10-a.json": `{"log": {"level": "info"}}`,
+	})
+
+	store, clear := NewSubscriptionStoreWithSize(NewSafeConfigStore(), 16)
+	defer clear()
+	events := store.Subscribe("")
+
+	v := NewWithStore(store)
+	assert.NoError(t, v.LoadFile(filepath.Join(dir, "config.json")))
+	assert.NoError(t, v.LoadDirectory(filepath.Join(dir, "conf.d"), false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := v.WatchFiles(ctx, 10*time.Millisecond)
+
+	nextEvent := func(t *testing.T) Event {
+		for {
+			select {
+			case event := <-events:
+				// reload results are covered by TestReloadValidation
+				if event.Key != ReloadEventKey {
+					return event
+				}
+			case err := <-errs:
+				t.Fatalf("unexpected reload error: %s", err)
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for event")
+			}
+		}
+	}
+
+	expectEvents := func(t *testing.T, expect ...Event) {
+		for _, event := range expect {
+			assert.Equal(t, event, nextEvent(t))
+		}
+	}
+
+	t.Run("should reload modified files", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"config.json": `{"db": {"host": "example.com"}}`,
+		})
+		expectEvents(t,
+			Event{Key: "db.host", Value: "example.com"},
+			Event{Key: "db.user", Value: nil},
+		)
+		assert.Nil(t, v.Get("db.user"))
+	})
+
+	t.Run("should load files created in watched directories", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"conf.d/20-b.json": `{"log": {"level": "debug"}}`,
+		})
+		expectEvents(t, Event{Key: "log.level", Value: "debug"})
+	})
+
+	t.Run("should unload files deleted from watched directories", func(t *testing.T) {
+		assert.NoError(t, os.Remove(filepath.Join(dir, "conf.d", "20-b.json")))
+		expectEvents(t, Event{Key: "log.level", Value: "info"})
+	})
+
+	t.Run("should report reload errors", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"config.json": `{"db": `,
+		})
+
+		select {
+		case err := <-errs:
+			assert.Error(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for reload error")
+		}
+		assert.Equal(t, "example.com", v.Get("db.host"))
+	})
+
+	cancel()
+	for range errs {
+	}
+}
@@ -0,0 +1,319 @@
+package venom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// writerMap is the collection of file extensions to the IOFileWriters that can
+// write files with the associated extensions
+var writerMap = map[string]IOFileWriter{
+	jsonKey:  JSONWriter,
+	jsoncKey: JSONWriter,
+	json5Key: JSONWriter,
+	xmlKey:   XMLWriter,
+}
+
+// IOFileWriter is the function signature for a function which can write a
+// map[string]interface{} to an io.Writer
+type IOFileWriter func(io.Writer, map[string]interface{}) error
+
+// RegisterWriter registers an IOFileWriter for the provided file extension.
+// Extensions are matched case-insensitively.
+func RegisterWriter(ext string, writer IOFileWriter) {
+	writerMap[normalizeExtension(ext)] = writer
+}
+
+// ErrNoFileWriter is the error returned when a file is attempted to be written
+// without a matching extension IOFileWriter
+type ErrNoFileWriter struct {
+	ext string
+}
+
+// Error implements the error interface and returns a custom error message for
+// the current ErrNoFileWriter instance
+func (e ErrNoFileWriter) Error() string {
+	return fmt.Sprintf("venom: no writer for extension %q", e.ext)
+}
+
+// JSONWriter is an IOFileWriter which writes indented JSON config data
+func JSONWriter(w io.Writer, data map[string]interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+// XMLWriter is an IOFileWriter which writes XML config data in the layout read
+// by XMLLoader. Keys are written as child elements of a "Config" root element,
+// slices are written as repeated elements, and keys prefixed with
+// DefaultXMLAttributePrefix or named DefaultXMLTextKey are written as
+// attributes and character data respectively.
+func XMLWriter(w io.Writer, data map[string]interface{}) error {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := encodeXMLElement(enc, "Config", data); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// encodeXMLElement writes val as an element with the provided name
+func encodeXMLElement(enc *xml.Encoder, name string, val interface{}) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("venom: key %q is not a valid xml element name", name)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	children, ok := toStringMap(val)
+	if !ok {
+		if val == nil {
+			return enc.EncodeElement("", start)
+		}
+		return enc.EncodeElement(fmt.Sprint(val), start)
+	}
+
+	keys := sortedKeys(children)
+	for _, key := range keys {
+		if strings.HasPrefix(key, DefaultXMLAttributePrefix) {
+			attr := strings.TrimPrefix(key, DefaultXMLAttributePrefix)
+			if !isXMLName(attr) {
+				return fmt.Errorf("venom: key %q is not a valid xml attribute name", key)
+			}
+			start.Attr = append(start.Attr, xml.Attr{
+				Name:  xml.Name{Local: attr},
+				Value: fmt.Sprint(children[key]),
+			})
+		}
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if text, ok := children[DefaultXMLTextKey]; ok {
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(text))); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		if key == DefaultXMLTextKey || strings.HasPrefix(key, DefaultXMLAttributePrefix) {
+			continue
+		}
+
+		items, ok := children[key].([]interface{})
+		if !ok {
+			items = []interface{}{children[key]}
+		}
+		for _, item := range items {
+			if err := encodeXMLElement(enc, key, item); err != nil {
+				return err
+			}
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// isXMLName returns true if the provided name may be used as the name of an
+// XML element or attribute. Names must start with a letter or underscore, and
+// may otherwise only contain letters, digits, hyphens, underscores and periods.
+// Colons are rejected as the name would be treated as namespaced.
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for index, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case index > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// toStringMap returns val as a map[string]interface{}, if it is a map
+func toStringMap(val interface{}) (map[string]interface{}, bool) {
+	switch actual := val.(type) {
+	case ConfigMap:
+		return actual, true
+	case map[string]interface{}:
+		return actual, true
+	}
+	return nil, false
+}
+
+// sortedKeys returns the keys of the provided map in lexical order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ErrUnreadableStore is the error returned when configs are written from a
+// ConfigStore which is not able to provide the config data of its levels. A
+// ConfigStore must be a LevelStore in order to write a ConfigLevel, and must
+// also be a LevelLister in order to write the effective configs.
+var ErrUnreadableStore = errors.New("venom: config store can not list and read its levels")
+
+// WriteOptions configures how configs are written by WriteFileWith
+type WriteOptions struct {
+	// NonDefault toggles writing only the values which differ from those
+	// stored in the DefaultLevel
+	NonDefault bool
+}
+
+// WriteFile writes the effective configs to the file at the provided path,
+// using the IOFileWriter registered for the files extension. The effective
+// configs are the config data stored in every ConfigLevel, with the values of
+// higher levels taking precedence. Values resolved on demand, such as those of
+// environment variables or flags, are not written.
+//
+// The file is written atomically, by writing the configs to a temporary file in
+// the same directory before renaming it over the destination.
+func (v *Venom) WriteFile(name string) error {
+	return v.WriteFileWith(name, WriteOptions{})
+}
+
+// WriteFileWith writes the effective configs to the file at the provided path,
+// as described by WriteFile, using the provided WriteOptions
+func (v *Venom) WriteFileWith(name string, opts WriteOptions) error {
+	_, lists := v.Store.(LevelLister)
+	if _, reads := v.Store.(LevelStore); !lists || !reads {
+		return ErrUnreadableStore
+	}
+
+	data := make(ConfigMap)
+	for _, level := range storeLevels(v.Store) {
+		data.merge(storeLevel(v.Store, level))
+	}
+
+	if opts.NonDefault {
+		data = withoutDefaults(data, storeLevel(v.Store, DefaultLevel))
+	}
+	return writeFile(name, data)
+}
+
+// WriteLevel writes the config data stored in the provided ConfigLevel to the
+// file at the provided path, as described by WriteFile
+func (v *Venom) WriteLevel(level ConfigLevel, name string) error {
+	if _, ok := v.Store.(LevelStore); !ok {
+		return ErrUnreadableStore
+	}
+
+	data := storeLevel(v.Store, level)
+	if data == nil {
+		data = make(ConfigMap)
+	}
+	return writeFile(name, data)
+}
+
+// withoutDefaults returns the entries of data whose values differ from those in
+// defaults. Nested ConfigMaps which only contain default values are omitted.
+func withoutDefaults(data, defaults ConfigMap) ConfigMap {
+	result := make(ConfigMap)
+	for key, val := range data {
+		def, ok := defaults[key]
+		if !ok {
+			result[key] = val
+			continue
+		}
+
+		nested, isMap := val.(ConfigMap)
+		nestedDefaults, defIsMap := def.(ConfigMap)
+		if isMap && defIsMap {
+			if diff := withoutDefaults(nested, nestedDefaults); len(diff) > 0 {
+				result[key] = diff
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(val, def) {
+			result[key] = val
+		}
+	}
+	return result
+}
+
+// writeFile atomically writes data to the named file, using the IOFileWriter
+// registered for the files extension
+func writeFile(name string, data ConfigMap) error {
+	ext := normalizeExtension(filepath.Ext(name))
+	writer, ok := writerMap[ext]
+	if !ok {
+		return ErrNoFileWriter{ext}
+	}
+
+	return writeAtomic(name, 0644, func(w io.Writer) error {
+		return writer(w, data)
+	})
+}
+
+// writeAtomic atomically replaces the named file with the contents written by
+// the provided function, via a temporary file which is synced to disk before
+// it is renamed over the file. The permissions of an existing file are kept,
+// otherwise the file is created with the provided mode.
+func writeAtomic(name string, mode os.FileMode, write func(io.Writer) error) error {
+	if info, err := os.Stat(name); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(name)
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	// removing the temporary file fails once it has been renamed
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir syncs the directory at the provided path, ensuring that a rename
+// within it is durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	// not every platform supports syncing directories, so a failure to sync is
+	// not treated as an error
+	d.Sync()
+	return nil
+}
@@ -0,0 +1,176 @@
+package venom
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFile(t *testing.T) {
+	testIO := []struct {
+		tc     string
+		name   string
+		opts   WriteOptions
+		err    error
+		expect ConfigMap
+	}{
+		{
+			tc:   "should write the effective configs as JSON",
+			name: "config.json",
+			expect: ConfigMap{
+				"db":  ConfigMap{"host": "example.com", "port": 5432.0},
+				"log": ConfigMap{"level": "debug"},
+			},
+		},
+		{
+			tc:   "should write only non-default values",
+			name: "config.json",
+			opts: WriteOptions{NonDefault: true},
+			expect: ConfigMap{
+				"db": ConfigMap{"host": "example.com"},
+			},
+		},
+		{
+			tc:   "should write the effective configs as XML",
+			name: "config.xml",
+			expect: ConfigMap{
+				"db":  ConfigMap{"host": "example.com", "port": "5432"},
+				"log": ConfigMap{"level": "debug"},
+			},
+		},
+		{
+			tc:   "should error on unknown file extension",
+			name: "config.ini",
+			err:  ErrNoFileWriter{ext: "ini"},
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			v := New()
+			v.SetDefault("db.host", "localhost")
+			v.SetDefault("db.port", 5432)
+			v.SetDefault("log.level", "debug")
+			v.SetLevel(FileLevel, "db.host", "example.com")
+			v.SetLevel(OverrideLevel, "log.level", "debug")
+
+			name := filepath.Join(t.TempDir(), test.name)
+			err := v.WriteFileWith(name, test.opts)
+			assertEqualErrors(t, test.err, err)
+			if test.err != nil {
+				_, err := os.Stat(name)
+				assert.True(t, os.IsNotExist(err))
+				return
+			}
+
+			written := New()
+			assert.NoError(t, written.LoadFile(name))
+			assert.Equal(t, test.expect, written.Level(FileLevel))
+		})
+	}
+}
+
+func TestWriteLevel(t *testing.T) {
+	v := New()
+	v.SetDefault("db.host", "localhost")
+	v.SetLevel(OverrideLevel, "db.host", "example.com")
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "override.json")
+	assert.NoError(t, ioutil.WriteFile(name, []byte(`{"stale": true}`), 0600))
+	assert.NoError(t, v.WriteLevel(OverrideLevel, name))
+
+	written := New()
+	assert.NoError(t, written.LoadFile(name))
+	assert.Equal(t, ConfigMap{"db": ConfigMap{"host": "example.com"}}, written.Level(FileLevel))
+
+	info, err := os.Stat(name)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "the mode of existing files is kept")
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1, "no temporary files are left behind")
+
+	assert.NoError(t, v.WriteLevel(EnvironmentLevel, name))
+	written = New()
+	assert.NoError(t, written.LoadFile(name))
+	assert.Empty(t, written.Level(FileLevel))
+}
+
+func TestWriteMinimalStore(t *testing.T) {
+	v := NewWithStore(minimalStore{NewDefaultConfigStore()})
+	v.SetDefault("db.host", "localhost")
+
+	name := filepath.Join(t.TempDir(), "config.json")
+	assert.Equal(t, ErrUnreadableStore, v.WriteFile(name))
+	assert.Equal(t, ErrUnreadableStore, v.WriteLevel(DefaultLevel, name))
+	_, err := os.Stat(name)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestXMLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	data := map[string]interface{}{
+		"server": map[string]interface{}{
+			"@name": "primary",
+			"port":  8080,
+			"tags":  []interface{}{"a", "b"},
+		},
+	}
+	assert.NoError(t, XMLWriter(&buf, data))
+
+	loaded, err := XMLLoader(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"server": map[string]interface{}{
+			"@name": "primary",
+			"port":  "8080",
+			"tags":  []interface{}{"a", "b"},
+		},
+	}, loaded)
+}
+
+func TestXMLWriterInvalidNames(t *testing.T) {
+	testIO := []struct {
+		tc  string
+		key string
+	}{
+		{tc: "should reject names starting with a digit", key: "1st"},
+		{tc: "should reject names containing spaces", key: "my key"},
+		{tc: "should reject names containing symbols", key: "$include"},
+		{tc: "should reject namespaced names", key: "ns:key"},
+		{tc: "should reject invalid attribute names", key: "@1st"},
+		{tc: "should reject empty attribute names", key: "@"},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := XMLWriter(&buf, map[string]interface{}{
+				"server": map[string]interface{}{test.key: "value"},
+			})
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.key)
+			}
+		})
+	}
+
+	t.Run("should not replace the file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"config.xml": "<Config></Config>\n"})
+		name := filepath.Join(dir, "config.xml")
+
+		v := New()
+		v.SetDefault("my key", "value")
+		assert.Error(t, v.WriteFile(name))
+
+		contents, err := ioutil.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, "<Config></Config>\n", string(contents))
+	})
+}
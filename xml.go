@@ -0,0 +1,238 @@
+package venom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	xmlKey = "xml"
+)
+
+var defaultXMLDecoder = &XMLDecoder{}
+
+// decimalPattern matches plain decimal literals, excluding the hexadecimal,
+// infinite and NaN values which are also accepted by strconv.ParseFloat
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// maxXMLDepth is the maximum nesting depth of XML elements, regardless of any
+// MaxDepth limit, which mirrors the nesting limit of encoding/json
+const maxXMLDepth = 10000
+
+// DefaultXMLAttributePrefix is the prefix applied to the keys of XML attributes
+// when an XMLDecoder is not configured with an explicit AttributePrefix.
+const DefaultXMLAttributePrefix = "@"
+
+// DefaultXMLTextKey is the key under which the character data of an XML element
+// is stored when that element also contains attributes or child elements.
+const DefaultXMLTextKey = "#text"
+
+// An XMLDecoder is capable of decoding XML config data into nested ConfigMaps.
+//
+// Child elements are mapped to nested maps keyed by their local name, and
+// repeated elements are collected into slices. Attributes are stored
+// alongside child elements, with their names prefixed by AttributePrefix. The
+// root element itself is dropped, meaning that its children become the top
+// level config keys.
+type XMLDecoder struct {
+	// AttributePrefix is prepended to the name of every attribute. If empty,
+	// DefaultXMLAttributePrefix is used.
+	AttributePrefix string
+
+	// TextKey is the key used to store the character data of elements which
+	// also contain attributes or child elements. If empty, DefaultXMLTextKey
+	// is used.
+	TextKey string
+
+	// InferTypes toggles the conversion of scalar text into bool, int or
+	// float64 values where possible. When disabled, all scalar values are
+	// returned as strings.
+	InferTypes bool
+}
+
+// XMLLoader is an IOFileLoader which loads XML config data using the default
+// XMLDecoder settings
+func XMLLoader(r io.Reader) (map[string]interface{}, error) {
+	return defaultXMLDecoder.Load(r)
+}
+
+// Load is an IOFileLoader which decodes the XML document read from r
+func (d *XMLDecoder) Load(r io.Reader) (map[string]interface{}, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := &limitCounter{limits: limitsOf(r)}
+	dec := xml.NewDecoder(bytes.NewReader(input))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return make(map[string]interface{}), nil
+		} else if err != nil {
+			return nil, newLoadError(xmlKey, input, int(dec.InputOffset()), err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		val, err := d.decodeElement(dec, start, "", 1, counter)
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			return nil, err
+		} else if err != nil {
+			return nil, newLoadError(xmlKey, input, int(dec.InputOffset()), err)
+		}
+
+		switch actual := val.(type) {
+		case map[string]interface{}:
+			return actual, nil
+		case string:
+			if actual == "" {
+				return make(map[string]interface{}), nil
+			}
+		}
+		return nil, fmt.Errorf("venom: xml root element %q contains no elements", start.Name.Local)
+	}
+}
+
+// decodeElement decodes the contents of the element opened by start, returning
+// either a scalar value or a map of its attributes and child elements. The
+// element is decoded as the provided key, at the provided depth, and the
+// limits of the counter are enforced as it is decoded.
+func (d *XMLDecoder) decodeElement(dec *xml.Decoder, start xml.StartElement, key string, depth int, counter *limitCounter) (interface{}, error) {
+	if depth > maxXMLDepth {
+		return nil, fmt.Errorf("venom: xml exceeds max depth of %d", maxXMLDepth)
+	}
+
+	children := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		name := d.attributePrefix() + attr.Name.Local
+		if err := d.countChild(counter, key, depth, name, children); err != nil {
+			return nil, err
+		}
+		if err := counter.str(childKey(key, name), attr.Value); err != nil {
+			return nil, err
+		}
+		children[name] = d.scalar(attr.Value)
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch actual := tok.(type) {
+		case xml.StartElement:
+			name := actual.Name.Local
+			if err := d.countChild(counter, key, depth, name, children); err != nil {
+				return nil, err
+			}
+			val, err := d.decodeElement(dec, actual, childKey(key, name), depth+1, counter)
+			if err != nil {
+				return nil, err
+			}
+			appendXMLChild(children, actual.Name.Local, val)
+		case xml.CharData:
+			text.Write(actual)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(children) == 0 {
+				if err := counter.str(key, content); err != nil {
+					return nil, err
+				}
+				return d.scalar(content), nil
+			}
+			if content != "" {
+				textKey := childKey(key, d.textKey())
+				if err := counter.key(textKey, d.textKey()); err != nil {
+					return nil, err
+				}
+				if err := counter.str(textKey, content); err != nil {
+					return nil, err
+				}
+				children[d.textKey()] = d.scalar(content)
+			}
+			return children, nil
+		}
+	}
+}
+
+// countChild enforces the limits of the counter on the named attribute or
+// child element of the element at key. Repeated child elements are only
+// counted once, as they are collected into a single slice. The depth of the
+// element is checked when its first child is found, as that is when it is
+// known to be a map rather than a scalar.
+func (d *XMLDecoder) countChild(counter *limitCounter, key string, depth int, name string, children map[string]interface{}) error {
+	if len(children) == 0 {
+		if err := counter.nested(key, depth); err != nil {
+			return err
+		}
+	}
+	if _, ok := children[name]; ok {
+		return nil
+	}
+	return counter.key(childKey(key, name), name)
+}
+
+// appendXMLChild stores val under key, converting the existing value into a
+// slice when an element is repeated
+func appendXMLChild(children map[string]interface{}, key string, val interface{}) {
+	existing, ok := children[key]
+	if !ok {
+		children[key] = val
+		return
+	}
+
+	if slice, ok := existing.([]interface{}); ok {
+		children[key] = append(slice, val)
+		return
+	}
+	children[key] = []interface{}{existing, val}
+}
+
+// scalar converts the provided text into the most specific type possible, if
+// type inference is enabled
+func (d *XMLDecoder) scalar(s string) interface{} {
+	if !d.InferTypes {
+		return s
+	}
+
+	if b, err := strconv.ParseBool(s); err == nil && (s == "true" || s == "false") {
+		return b
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if decimalPattern.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+func (d *XMLDecoder) attributePrefix() string {
+	if d.AttributePrefix == "" {
+		return DefaultXMLAttributePrefix
+	}
+	return d.AttributePrefix
+}
+
+func (d *XMLDecoder) textKey() string {
+	if d.TextKey == "" {
+		return DefaultXMLTextKey
+	}
+	return d.TextKey
+}
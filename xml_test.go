@@ -0,0 +1,128 @@
+package venom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXMLDecoder(t *testing.T) {
+	testIO := []struct {
+		tc      string
+		decoder *XMLDecoder
+		input   string
+		expect  map[string]interface{}
+		err     bool
+	}{
+		{
+			tc:      "should drop the root element",
+			decoder: &XMLDecoder{},
+			input:   `<Config><foo>Bar</foo><level>5.0</level></Config>`,
+			expect: map[string]interface{}{
+				"foo":   "Bar",
+				"level": "5.0",
+			},
+		},
+		{
+			tc:      "should infer scalar types",
+			decoder: &XMLDecoder{InferTypes: true},
+			input:   `<Config><level>5.0</level><port>8080</port><debug>true</debug><name>app</name></Config>`,
+			expect: map[string]interface{}{
+				"level": 5.0,
+				"port":  8080,
+				"debug": true,
+				"name":  "app",
+			},
+		},
+		{
+			tc:      "should only infer floats from decimal literals",
+			decoder: &XMLDecoder{InferTypes: true},
+			input:   `<Config><a>nan</a><b>Inf</b><c>-Infinity</c><d>0x1p4</d><e>.5</e><f>1e3</f><g>1_000.5</g></Config>`,
+			expect: map[string]interface{}{
+				"a": "nan",
+				"b": "Inf",
+				"c": "-Infinity",
+				"d": "0x1p4",
+				"e": 0.5,
+				"f": 1000.0,
+				"g": "1_000.5",
+			},
+		},
+		{
+			tc:      "should map nested elements to nested maps",
+			decoder: &XMLDecoder{},
+			input:   `<Config><log><level>info</level><file>/tmp/log</file></log></Config>`,
+			expect: map[string]interface{}{
+				"log": map[string]interface{}{
+					"level": "info",
+					"file":  "/tmp/log",
+				},
+			},
+		},
+		{
+			tc:      "should map repeated elements to slices",
+			decoder: &XMLDecoder{},
+			input:   `<Config><host>a</host><host>b</host><host>c</host></Config>`,
+			expect: map[string]interface{}{
+				"host": []interface{}{"a", "b", "c"},
+			},
+		},
+		{
+			tc:      "should map attributes to prefixed keys",
+			decoder: &XMLDecoder{},
+			input:   `<Config><db driver="pg"><host>localhost</host></db><name lang="en">app</name></Config>`,
+			expect: map[string]interface{}{
+				"db": map[string]interface{}{
+					"@driver": "pg",
+					"host":    "localhost",
+				},
+				"name": map[string]interface{}{
+					"@lang": "en",
+					"#text": "app",
+				},
+			},
+		},
+		{
+			tc:      "should use a custom attribute prefix and text key",
+			decoder: &XMLDecoder{AttributePrefix: "_", TextKey: "value"},
+			input:   `<Config><name lang="en">app</name></Config>`,
+			expect: map[string]interface{}{
+				"name": map[string]interface{}{
+					"_lang": "en",
+					"value": "app",
+				},
+			},
+		},
+		{
+			tc:      "should load an empty document",
+			decoder: &XMLDecoder{},
+			input:   `<Config></Config>`,
+			expect:  map[string]interface{}{},
+		},
+		{
+			tc:      "should error on a root element with only text",
+			decoder: &XMLDecoder{},
+			input:   `<Config>text</Config>`,
+			err:     true,
+		},
+		{
+			tc:      "should error on malformed documents",
+			decoder: &XMLDecoder{},
+			input:   `<Config><foo>Bar</Config>`,
+			err:     true,
+		},
+	}
+
+	for _, test := range testIO {
+		t.Run(test.tc, func(t *testing.T) {
+			actual, err := test.decoder.Load(strings.NewReader(test.input))
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}